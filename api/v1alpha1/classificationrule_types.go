@@ -21,13 +21,24 @@ import (
 )
 
 // ClassificationRuleSpec defines the desired state of ClassificationRule
+// +kubebuilder:validation:XValidation:rule="has(self.targetAPIVersion) || self.targetKind in ['Pod', 'Node', 'Namespace', 'Service', 'Deployment', 'StatefulSet', 'DaemonSet', 'ReplicaSet', 'Job', 'CronJob']",message="targetAPIVersion is required for targetKinds other than Pod, Node, Namespace, Service, Deployment, StatefulSet, DaemonSet, ReplicaSet, Job and CronJob"
 type ClassificationRuleSpec struct {
 
-	// TargetKind specifies the Kubernetes resource type to apply the rule to
-	// +kubebuilder:validation:Enum=Pod;Node;Namespace;Service;Deployment;StatefulSet;DaemonSet;ReplicaSet;Job;CronJob
+	// TargetKind specifies the Kubernetes resource type to apply the rule to.
+	// Pod, Node, Namespace, Service, Deployment, StatefulSet, DaemonSet, ReplicaSet, Job and CronJob are built in
+	// and support their kind-specific criteria. Any other kind needs TargetAPIVersion.
+	// +kubebuilder:validation:Pattern=`^[A-Z][A-Za-z0-9]*$`
 	// +kubebuilder:default=Pod
 	TargetKind string `json:"targetKind"`
 
+	// TargetAPIVersion is the apiVersion of TargetKind, e.g. "cert-manager.io/v1". It may be omitted for the
+	// built-in kinds. Other kinds are labelled as unstructured objects and only support commonMatch and fieldMatch;
+	// their scope is discovered from the API server. The manager needs get, list, watch, update and patch
+	// permissions on such resources, which are not part of the generated RBAC role.
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?v[0-9]+((alpha|beta)[0-9]+)?$`
+	// +optional
+	TargetAPIVersion string `json:"targetAPIVersion,omitempty"`
+
 	// Match defines the resource fields to match for labelling
 	// Key = field name (e.g., "image", "name", "namespace")
 	// Value = expected value to match (e.g., "nginx", "prod_proxy", "production")
@@ -40,7 +51,11 @@ type ClassificationRuleSpec struct {
 	// + optional
 	Labels map[string]string `json:"labels,omitempty"`
 
-	// ConflictPolicy defines the policy to apply when there is a conflict in labeling
+	// ConflictPolicy defines the policy to apply when a target already carries one of the
+	// rule's label keys with a different value:
+	// Overwrite replaces the existing value, Merge only adds missing keys and keeps existing values,
+	// Ignore leaves the conflicting target untouched, and Error refuses to label the target and
+	// marks the rule as Degraded.
 	// +kubebuilder:validation:Enum=Overwrite;Merge;Ignore;Error
 	// +kubebuilder:default=Merge
 	ConflictPolicy string `json:"conflictPolicy,omitempty"`
@@ -50,22 +65,29 @@ type ClassificationRuleSpec struct {
 	// +kubebuilder:default=false
 	Suspend bool `json:"suspend,omitempty"`
 
-	// RefreshInterval defines how often the rule should be re-evaluated and reapplied.
-	// Must be a valid duration string (e.g., "30s", "5m", "1h").
-	// Defaults to 30s if not specified.
+	// Sticky keeps the rule's labels on targets that stop matching it, for one-shot classification.
+	// By default labels owned by the rule are withdrawn from targets that no longer match.
+	// +optional
+	// +kubebuilder:default=false
+	Sticky bool `json:"sticky,omitempty"`
+
+	// RefreshInterval optionally re-evaluates and reapplies the rule periodically as a safety resync.
+	// Rules are re-evaluated whenever a target object changes, so this is not required for labelling.
+	// Must be a valid duration string (e.g., "30s", "5m", "1h"). No periodic resync happens when empty.
 	// +optional
-	// +kubebuilder:default="30s"
 	RefreshInterval string `json:"refreshInterval,omitempty"`
 }
 
 // MatchCriteria defines the criteria for matching resources.
-// It contains common fields plus resource-type-specific matchers.
+// It contains common fields plus resource-type-specific matchers. Everything set must match; AnyOf, AllOf and
+// Not nest further criteria to express alternatives and exclusions.
 type MatchCriteria struct {
 	// Common criteria applicable to all resource types
 	// +optional
 	CommonMatch *CommonMatchCriteria `json:"commonMatch,omitempty"`
 
-	// Pod-specific match criteria
+	// Pod-specific match criteria. For Deployment, StatefulSet, DaemonSet, ReplicaSet, Job and CronJob
+	// targets they are evaluated against the workload's pod template.
 	// +optional
 	PodMatch *PodMatchCriteria `json:"podMatch,omitempty"`
 
@@ -76,6 +98,69 @@ type MatchCriteria struct {
 	// Deployment-specific match criteria
 	// +optional
 	DeploymentMatch *DeploymentMatchCriteria `json:"deploymentMatch,omitempty"`
+
+	// Namespace-specific match criteria
+	// +optional
+	NamespaceMatch *NamespaceMatchCriteria `json:"namespaceMatch,omitempty"`
+
+	// Service-specific match criteria
+	// +optional
+	ServiceMatch *ServiceMatchCriteria `json:"serviceMatch,omitempty"`
+
+	// StatefulSet-specific match criteria
+	// +optional
+	StatefulSetMatch *StatefulSetMatchCriteria `json:"statefulSetMatch,omitempty"`
+
+	// DaemonSet-specific match criteria
+	// +optional
+	DaemonSetMatch *DaemonSetMatchCriteria `json:"daemonSetMatch,omitempty"`
+
+	// ReplicaSet-specific match criteria
+	// +optional
+	ReplicaSetMatch *ReplicaSetMatchCriteria `json:"replicaSetMatch,omitempty"`
+
+	// Job-specific match criteria. For CronJob targets they are evaluated against the job template.
+	// +optional
+	JobMatch *JobMatchCriteria `json:"jobMatch,omitempty"`
+
+	// CronJob-specific match criteria
+	// +optional
+	CronJobMatch *CronJobMatchCriteria `json:"cronJobMatch,omitempty"`
+
+	// FieldMatch lists field requirements that must all hold. They are evaluated against the object's
+	// serialized form, so they apply to every kind, including targets selected with TargetAPIVersion.
+	// +optional
+	// +listType=atomic
+	FieldMatch []FieldRequirement `json:"fieldMatch,omitempty"`
+
+	// Expression is a CEL expression that must evaluate to true for the target to match. The target is bound
+	// as "object" in its serialized form, e.g. "object.spec.containers.exists(c, c.image.startsWith('nginx'))".
+	// Evaluation errors, such as selecting a missing field (guard with has()), and evaluations exceeding the
+	// cost limit do not match.
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	Expression string `json:"expression,omitempty"`
+
+	// AnyOf matches when at least one of the nested criteria matches.
+	// The CRD schema only requires each branch to be an object. Nested criteria are validated by the admission
+	// webhook, which rejects unknown fields and branches without criteria.
+	// +optional
+	// +kubebuilder:validation:items:Type=object
+	// +kubebuilder:validation:items:XPreserveUnknownFields
+	AnyOf []MatchCriteria `json:"anyOf,omitempty"`
+
+	// AllOf matches when every one of the nested criteria matches.
+	// +optional
+	// +kubebuilder:validation:items:Type=object
+	// +kubebuilder:validation:items:XPreserveUnknownFields
+	AllOf []MatchCriteria `json:"allOf,omitempty"`
+
+	// Not matches when the nested criteria do not match, e.g. to exclude a namespace.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Not *MatchCriteria `json:"not,omitempty"`
 }
 
 // ClassificationRuleStatus defines the observed state of ClassificationRule.
@@ -95,6 +180,11 @@ type ClassificationRuleStatus struct {
 	// +optional
 	MatchedResourcesCount int32 `json:"MatchedResourceCount,omitempty"`
 
+	// conflicts lists the label conflicts detected during the last reconciliation and how each was resolved.
+	// +optional
+	// +listType=atomic
+	Conflicts []LabelConflict `json:"conflicts,omitempty"`
+
 	// lastError provides details of the last error encountered while applying the rule.
 	// +optional
 	LastError string `json:"lastError,omitempty"`
@@ -105,6 +195,29 @@ type ClassificationRuleStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// LabelConflict records a rule label whose key was already present on a target with a different value.
+type LabelConflict struct {
+	// resourceName is the name of the conflicting target.
+	ResourceName string `json:"resourceName"`
+
+	// resourceNamespace is the namespace of the conflicting target. Empty for cluster-scoped targets.
+	// +optional
+	ResourceNamespace string `json:"resourceNamespace,omitempty"`
+
+	// key is the conflicting label key.
+	Key string `json:"key"`
+
+	// existingValue is the value found on the target.
+	ExistingValue string `json:"existingValue"`
+
+	// desiredValue is the value the rule wanted to apply.
+	DesiredValue string `json:"desiredValue"`
+
+	// resolution is the outcome chosen by the rule's ConflictPolicy.
+	// One of Overwritten, Kept, Skipped or Rejected.
+	Resolution string `json:"resolution"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
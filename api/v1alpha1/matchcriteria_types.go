@@ -1,51 +1,111 @@
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // CommonMatchCriteria contains criteria common to most resource types
 type CommonMatchCriteria struct {
 	// Labels is a map of label keys and values to match
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
 
-	// Annotations is a map of annotation keys and values to match
+	// Annotations is a map of annotation keys and values to match. A value of the form "regex:<pattern>"
+	// matches annotation values that fully match the regular expression (e.g., "regex:team-.*").
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 
+	// LabelRequirements lists label requirements that must all hold. Exists, DoesNotExist, In and NotIn
+	// requirements are also used to narrow the list request sent to the API server.
+	// +optional
+	// +listType=atomic
+	LabelRequirements []MetadataRequirement `json:"labelRequirements,omitempty"`
+
+	// AnnotationRequirements lists annotation requirements that must all hold.
+	// +optional
+	// +listType=atomic
+	AnnotationRequirements []MetadataRequirement `json:"annotationRequirements,omitempty"`
+
 	// Namespace is the namespace name to match. Exact string match.
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
 
-	// Name is the resource name to match. Supports wildcard patterns (* and ?).
+	// NamespaceSelector matches namespaced targets by the labels of the namespace they live in.
+	// It is ignored for cluster-scoped targets, like Namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Name is the resource name to match. Supports wildcard patterns:
+	// * matches any run of characters and ? matches exactly one (e.g., "web-*", "node-??").
 	// +optional
 	Name string `json:"name,omitempty"`
 }
 
+// MetadataRequirement matches a label or annotation by key, modelled on metav1.LabelSelectorRequirement.
+type MetadataRequirement struct {
+	// Key is the label or annotation key.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Operator relates the key to Values:
+	// Exists and DoesNotExist check the key's presence and take no values.
+	// In and NotIn require the value to be (or not be) one of Values; NotIn also holds when the key is missing.
+	// Prefix, Suffix, Glob and Regex require the value to start with, end with, match the wildcard pattern
+	// (* and ?) or fully match the regular expression of any of Values.
+	// +kubebuilder:validation:Enum=Exists;DoesNotExist;In;NotIn;Prefix;Suffix;Glob;Regex
+	Operator string `json:"operator"`
+
+	// Values are the operands of every operator but Exists and DoesNotExist.
+	// +optional
+	// +listType=atomic
+	Values []string `json:"values,omitempty"`
+}
+
 // PodMatchCriteria contains Pod-specific match criteria
 type PodMatchCriteria struct {
-	// Images is a list of container image patterns to match.
-	// Each pattern supports wildcard matching (* and ?).
+	// Images is a list of container image patterns to match; a Pod matches if any container, including init and
+	// ephemeral containers, matches any pattern.
+	// Each pattern supports wildcard matching (* and ?, where * also spans '/') and is compared against the
+	// fully qualified and short forms of the image, so "nginx" matches "docker.io/library/nginx:1.27".
+	// Patterns without a tag or digest match any tag; "nginx:1.*" or "app@sha256:*" also match the tag or digest.
 	// +optional
 	Images []string `json:"images,omitempty"`
 
-	// CPURequests matches Pods with CPU requests matching the specified value.
-	// Supports comparison operators (e.g., ">1", "<=500m").
+	// CPURequests matches Pods whose total CPU request satisfies the expression.
+	// Supports comparison operators (<, <=, ==, !=, >=, >) and inclusive ranges (e.g., ">1", "<=500m", "250m..2").
+	// Totals are summed across containers; see IncludeInitContainers and MissingResourcePolicy.
 	// +optional
 	CPURequests string `json:"cpuRequests,omitempty"`
 
-	// MemoryRequests matches Pods with memory requests matching the specified value.
-	// Supports comparison operators (e.g., ">1Gi", "<=512Mi").
+	// MemoryRequests matches Pods whose total memory request satisfies the expression.
+	// Supports comparison operators and ranges (e.g., ">1Gi", "<=512Mi", "256Mi..1Gi").
 	// +optional
 	MemoryRequests string `json:"memoryRequests,omitempty"`
 
-	// CPULimits matches Pods with CPU limits matching the specified value.
-	// Supports comparison operators.
+	// CPULimits matches Pods whose total CPU limit satisfies the expression.
+	// Supports comparison operators and ranges.
 	// +optional
 	CPULimits string `json:"cpuLimits,omitempty"`
 
-	// MemoryLimits matches Pods with memory limits matching the specified value.
-	// Supports comparison operators.
+	// MemoryLimits matches Pods whose total memory limit satisfies the expression.
+	// Supports comparison operators and ranges.
 	// +optional
 	MemoryLimits string `json:"memoryLimits,omitempty"`
 
+	// IncludeInitContainers makes resource comparisons use the Pod's effective value as the scheduler computes it:
+	// the larger of the sum across regular containers and the largest single init container.
+	// By default only regular containers are summed.
+	// +optional
+	// +kubebuilder:default=false
+	IncludeInitContainers bool `json:"includeInitContainers,omitempty"`
+
+	// MissingResourcePolicy defines how containers without the compared request or limit are treated.
+	// Zero counts them as 0, NoMatch makes the resource criterion fail for the Pod.
+	// +optional
+	// +kubebuilder:validation:Enum=Zero;NoMatch
+	// +kubebuilder:default=Zero
+	MissingResourcePolicy string `json:"missingResourcePolicy,omitempty"`
+
 	// NodeSelector is a map of node labels to match for Pod scheduling.
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -62,6 +122,123 @@ type PodMatchCriteria struct {
 	// Valid values: Always, OnFailure, Never
 	// +optional
 	RestartPolicy string `json:"restartPolicy,omitempty"`
+
+	// Security matches Pods by their security settings, e.g. to classify privileged or host-level workloads.
+	// +optional
+	Security *PodSecurityCriteria `json:"security,omitempty"`
+
+	// Status matches Pods by their runtime state. It is only available for the Pod targetKind. Pods are
+	// re-evaluated as their status changes, so labels are withdrawn once the state clears (unless the rule is sticky).
+	// +optional
+	Status *PodStatusCriteria `json:"status,omitempty"`
+}
+
+// PodStatusCriteria matches the runtime state of a Pod as reported in its status.
+type PodStatusCriteria struct {
+	// Phases matches Pods in any of these phases.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:items:Enum=Pending;Running;Succeeded;Failed;Unknown
+	Phases []string `json:"phases,omitempty"`
+
+	// QOSClasses matches Pods of any of these QoS classes.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:items:Enum=Guaranteed;Burstable;BestEffort
+	QOSClasses []string `json:"qosClasses,omitempty"`
+
+	// RestartCount matches Pods whose highest container restart count satisfies the expression.
+	// Supports comparison operators and ranges (e.g., ">5", "0", "1..3"). Init containers are included.
+	// +optional
+	RestartCount string `json:"restartCount,omitempty"`
+
+	// Reasons matches Pods with a container whose current waiting or terminated reason, or last termination
+	// reason, is one of these, e.g. "CrashLoopBackOff", "OOMKilled", "ImagePullBackOff" or "Error".
+	// +optional
+	// +listType=atomic
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Ready matches Pods whose Ready condition is true (true) or not (false).
+	// +optional
+	Ready *bool `json:"ready,omitempty"`
+
+	// PendingFor matches Pods that have been Pending, measured from their creation, for a duration satisfying
+	// the expression (e.g., ">10m"). Rules are re-evaluated when a pending Pod crosses the threshold.
+	// +optional
+	PendingFor string `json:"pendingFor,omitempty"`
+}
+
+// PodSecurityCriteria matches the security settings of a Pod and its containers. Container criteria are evaluated
+// against the effective settings of regular, init and ephemeral containers, with container security contexts
+// overriding the Pod's. Every criterion set must match; each one is evaluated on its own according to Containers.
+type PodSecurityCriteria struct {
+	// Containers defines whether container criteria must hold for Any container (default) or for All of them.
+	// All requires at least one container, so a Pod without containers never matches container criteria.
+	// Combine several security blocks with allOf to mix both semantics.
+	// +optional
+	// +kubebuilder:validation:Enum=Any;All
+	// +kubebuilder:default=Any
+	Containers string `json:"containers,omitempty"`
+
+	// Privileged matches containers running in privileged mode (true) or not (false).
+	// +optional
+	Privileged *bool `json:"privileged,omitempty"`
+
+	// AddedCapabilities matches containers that add any of these Linux capabilities, e.g. "NET_ADMIN" or "SYS_ADMIN".
+	// Names are compared case-insensitively with or without the "CAP_" prefix; a container adding "ALL" matches any.
+	// +optional
+	// +listType=atomic
+	AddedCapabilities []string `json:"addedCapabilities,omitempty"`
+
+	// AllowPrivilegeEscalation matches containers whose allowPrivilegeEscalation is true or false.
+	// Unset counts as true, the Kubernetes default.
+	// +optional
+	AllowPrivilegeEscalation *bool `json:"allowPrivilegeEscalation,omitempty"`
+
+	// RunAsNonRoot matches containers whose effective runAsNonRoot is true or false. Unset counts as false.
+	// +optional
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+
+	// RunAsRoot matches containers whose effective runAsUser is 0 (true) or not (false). Containers without
+	// runAsUser run as the image's user and do not count as root.
+	// +optional
+	RunAsRoot *bool `json:"runAsRoot,omitempty"`
+
+	// ReadOnlyRootFilesystem matches containers whose readOnlyRootFilesystem is true or false. Unset counts as false.
+	// +optional
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// SeccompProfileTypes matches containers whose effective seccomp profile type is one of these.
+	// Unset matches containers without a seccomp profile.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:items:Enum=Unconfined;RuntimeDefault;Localhost;Unset
+	SeccompProfileTypes []string `json:"seccompProfileTypes,omitempty"`
+
+	// AppArmorProfileTypes matches containers whose effective AppArmor profile type is one of these.
+	// Unset matches containers without an appArmorProfile; the deprecated AppArmor annotations are not considered.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:items:Enum=Unconfined;RuntimeDefault;Localhost;Unset
+	AppArmorProfileTypes []string `json:"appArmorProfileTypes,omitempty"`
+
+	// HostPID matches Pods sharing the host's PID namespace (true) or not (false).
+	// +optional
+	HostPID *bool `json:"hostPID,omitempty"`
+
+	// HostIPC matches Pods sharing the host's IPC namespace (true) or not (false).
+	// +optional
+	HostIPC *bool `json:"hostIPC,omitempty"`
+
+	// HostPath matches Pods that have at least one hostPath volume (true) or none (false).
+	// +optional
+	HostPath *bool `json:"hostPath,omitempty"`
+
+	// HostPathPrefixes matches Pods with a hostPath volume at or below one of these absolute paths. Prefixes match
+	// whole path elements, so "/var/run" matches "/var/run/docker.sock" but not "/var/runtime".
+	// +optional
+	// +listType=atomic
+	HostPathPrefixes []string `json:"hostPathPrefixes,omitempty"`
 }
 
 // NodeMatchCriteria contains Node-specific match criteria
@@ -76,13 +253,57 @@ type NodeMatchCriteria struct {
 	// +optional
 	OSLabels []string `json:"osLabels,omitempty"`
 
+	// LegacyArchLabels matches nodes whose deprecated beta.kubernetes.io/arch label is one of these values.
+	// +optional
+	// +listType=atomic
+	LegacyArchLabels []string `json:"legacyArchLabels,omitempty"`
+
+	// LegacyOSLabels matches nodes whose deprecated beta.kubernetes.io/os label is one of these values.
+	// +optional
+	// +listType=atomic
+	LegacyOSLabels []string `json:"legacyOSLabels,omitempty"`
+
+	// Zones matches nodes whose topology.kubernetes.io/zone label is one of these values.
+	// +optional
+	// +listType=atomic
+	Zones []string `json:"zones,omitempty"`
+
+	// Regions matches nodes whose topology.kubernetes.io/region label is one of these values.
+	// +optional
+	// +listType=atomic
+	Regions []string `json:"regions,omitempty"`
+
+	// ControlPlane matches nodes with the control-plane role (true) or without it (false), as given by the
+	// node-role.kubernetes.io/control-plane label or the legacy node-role.kubernetes.io/master label.
+	// +optional
+	ControlPlane *bool `json:"controlPlane,omitempty"`
+
+	// Unschedulable matches cordoned nodes (true) or schedulable ones (false).
+	// +optional
+	Unschedulable *bool `json:"unschedulable,omitempty"`
+
+	// Conditions matches nodes whose conditions all have the given status, each in "Type=Status" format
+	// (e.g., "MemoryPressure=True", "Ready=False"). A bare type means "Type=True". Conditions the node does not
+	// report have status Unknown.
+	// +optional
+	// +listType=atomic
+	Conditions []string `json:"conditions,omitempty"`
+
+	// ProviderIDPrefixes matches nodes whose spec.providerID starts with any of these prefixes
+	// (e.g., "aws://", "gce://my-project/").
+	// +optional
+	// +listType=atomic
+	ProviderIDPrefixes []string `json:"providerIDPrefixes,omitempty"`
+
 	// Taints matches nodes with specific taints.
 	// Each taint is matched as key=value:effect format.
 	// +optional
 	Taints []string `json:"taints,omitempty"`
 
-	// KernelVersion matches nodes with kernel versions matching the pattern.
-	// Supports comparison operators.
+	// KernelVersion matches nodes by kernel version. A bare value matches as a substring (e.g., "azure", "5.15.0-1057").
+	// Comparison operators and inclusive ranges compare semantically (e.g., ">=5.15", "5.10..6.1"), parsing distro
+	// kernels such as "5.15.0-1057-azure" as 5.15.0.1057. Versions are compared up to the operand's precision,
+	// so ">=5.15" matches 5.15.0-1057 and ">5.15" requires 5.16 or later.
 	// +optional
 	KernelVersion string `json:"kernelVersion,omitempty"`
 
@@ -90,12 +311,35 @@ type NodeMatchCriteria struct {
 	// Examples: docker, containerd, cri-o
 	// +optional
 	ContainerRuntime string `json:"containerRuntime,omitempty"`
+
+	// ContainerRuntimeVersion matches nodes whose container runtime version (e.g., "1.7.13" from
+	// "containerd://1.7.13") satisfies the expression. Supports comparison operators and ranges (e.g., ">=1.7");
+	// a bare value is an equality match up to its precision.
+	// +optional
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion,omitempty"`
+
+	// KubeletVersion matches nodes whose kubelet version (e.g., "v1.30.2") satisfies the expression.
+	// Supports comparison operators and ranges (e.g., "<1.29", "1.28..1.30"); a bare value such as "1.30"
+	// matches any 1.30 patch release.
+	// +optional
+	KubeletVersion string `json:"kubeletVersion,omitempty"`
+
+	// Capacity matches nodes whose reported capacity satisfies every expression, keyed by resource name, e.g.
+	// {"cpu": ">=16", "memory": ">=64Gi", "nvidia.com/gpu": ">0", "hugepages-2Mi": ">0"}. Expressions use the same
+	// comparison operators and ranges as the Pod resource criteria. Resources a node does not report count as 0.
+	// +optional
+	Capacity map[string]string `json:"capacity,omitempty"`
+
+	// Allocatable matches nodes whose allocatable resources satisfy every expression, like Capacity.
+	// +optional
+	Allocatable map[string]string `json:"allocatable,omitempty"`
 }
 
 // DeploymentMatchCriteria contains Deployment-specific match criteria
 type DeploymentMatchCriteria struct {
-	// Replicas matches Deployments with specific replica count.
-	// Supports comparison operators (e.g., ">3", "==5").
+	// Replicas matches Deployments whose desired replica count satisfies the expression.
+	// Supports comparison operators (<, <=, ==, !=, >=, >) and inclusive ranges (e.g., ">3", "==5", "2..4").
+	// A bare number is an exact match.
 	// +optional
 	Replicas string `json:"replicas,omitempty"`
 
@@ -109,3 +353,224 @@ type DeploymentMatchCriteria struct {
 	// +optional
 	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
 }
+
+// NamespaceMatchCriteria contains Namespace-specific match criteria
+type NamespaceMatchCriteria struct {
+	// Phase matches Namespaces in the given lifecycle phase.
+	// Valid values: Active, Terminating
+	// +optional
+	// +kubebuilder:validation:Enum=Active;Terminating
+	Phase string `json:"phase,omitempty"`
+
+	// HasLabels matches Namespaces that carry every listed label key, whatever its value.
+	// +optional
+	HasLabels []string `json:"hasLabels,omitempty"`
+
+	// HasResourceQuota matches Namespaces that contain at least one ResourceQuota (true) or none (false).
+	// +optional
+	HasResourceQuota *bool `json:"hasResourceQuota,omitempty"`
+
+	// Age matches Namespaces by the time since their creation.
+	// Supports comparison operators and ranges over durations (e.g., ">=24h", "<30m", "1h..168h").
+	// The rule is re-evaluated when a Namespace's age crosses a threshold, without waiting for a Namespace event.
+	// +optional
+	Age string `json:"age,omitempty"`
+}
+
+// ServiceMatchCriteria contains Service-specific match criteria
+type ServiceMatchCriteria struct {
+	// Type matches Services of the given type.
+	// Valid values: ClusterIP, NodePort, LoadBalancer, ExternalName
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer;ExternalName
+	Type string `json:"type,omitempty"`
+
+	// Ports matches Services exposing every listed port, written as "port" or "port/protocol"
+	// (e.g., "443", "53/UDP"). Without a protocol any protocol matches.
+	// +optional
+	Ports []string `json:"ports,omitempty"`
+
+	// Protocols matches Services exposing at least one port for each listed protocol.
+	// Valid values: TCP, UDP, SCTP
+	// +optional
+	// +kubebuilder:validation:items:Enum=TCP;UDP;SCTP
+	Protocols []string `json:"protocols,omitempty"`
+
+	// Selector matches Services whose pod selector contains every listed key and value.
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// Headless matches headless Services (clusterIP: None) when true and all others when false.
+	// +optional
+	Headless *bool `json:"headless,omitempty"`
+
+	// ExternalTrafficPolicy matches Services with the given external traffic policy.
+	// Valid values: Cluster, Local
+	// +optional
+	// +kubebuilder:validation:Enum=Cluster;Local
+	ExternalTrafficPolicy string `json:"externalTrafficPolicy,omitempty"`
+}
+
+// StatefulSetMatchCriteria contains StatefulSet-specific match criteria
+type StatefulSetMatchCriteria struct {
+	// Replicas matches StatefulSets whose desired replica count satisfies the expression.
+	// Supports comparison operators and inclusive ranges (e.g., ">=3", "1..5").
+	// +optional
+	Replicas string `json:"replicas,omitempty"`
+
+	// PodManagementPolicy matches StatefulSets with the given pod management policy.
+	// Valid values: OrderedReady, Parallel
+	// +optional
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	PodManagementPolicy string `json:"podManagementPolicy,omitempty"`
+
+	// HasVolumeClaimTemplates matches StatefulSets with (true) or without (false) volumeClaimTemplates.
+	// +optional
+	HasVolumeClaimTemplates *bool `json:"hasVolumeClaimTemplates,omitempty"`
+
+	// VolumeClaimTemplates matches StatefulSets declaring every listed volumeClaimTemplate name.
+	// Each name supports wildcard patterns (* and ?).
+	// +optional
+	VolumeClaimTemplates []string `json:"volumeClaimTemplates,omitempty"`
+
+	// StorageClass matches StatefulSets with at least one volumeClaimTemplate using a storage class matching
+	// the pattern (* and ? wildcards). Templates without a storageClassName are not considered.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// DaemonSetMatchCriteria contains DaemonSet-specific match criteria
+type DaemonSetMatchCriteria struct {
+	// UpdateStrategy matches DaemonSets with the given update strategy.
+	// Valid values: RollingUpdate, OnDelete
+	// +optional
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	UpdateStrategy string `json:"updateStrategy,omitempty"`
+
+	// NodeSelector matches DaemonSets whose pod template node selector contains every listed key and value.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations matches DaemonSets whose pod template tolerates every listed taint, written as
+	// key[=value][:effect] (e.g., "node-role.kubernetes.io/control-plane:NoSchedule", "dedicated=gpu:NoExecute").
+	// Without a value any toleration for the key matches; without an effect tolerating any effect is enough.
+	// +optional
+	Tolerations []string `json:"tolerations,omitempty"`
+}
+
+// ReplicaSetMatchCriteria contains ReplicaSet-specific match criteria
+type ReplicaSetMatchCriteria struct {
+	// Replicas matches ReplicaSets whose desired replica count satisfies the expression.
+	// Supports comparison operators and inclusive ranges (e.g., "==0", ">1").
+	// +optional
+	Replicas string `json:"replicas,omitempty"`
+
+	// HasOwner matches ReplicaSets that are managed by a controller (true) or standalone (false).
+	// +optional
+	HasOwner *bool `json:"hasOwner,omitempty"`
+
+	// OwnerKind matches ReplicaSets whose controller owner reference has the given kind (e.g., "Deployment").
+	// +optional
+	OwnerKind string `json:"ownerKind,omitempty"`
+
+	// OwnerName matches ReplicaSets whose controller owner reference name matches the pattern (* and ? wildcards).
+	// +optional
+	OwnerName string `json:"ownerName,omitempty"`
+}
+
+// JobMatchCriteria contains Job-specific match criteria.
+// Integer criteria support comparison operators and inclusive ranges (e.g., ">3", "1..5").
+type JobMatchCriteria struct {
+	// BackoffLimit matches Jobs whose backoffLimit satisfies the expression. An unset limit counts as 6.
+	// +optional
+	BackoffLimit string `json:"backoffLimit,omitempty"`
+
+	// Completions matches Jobs whose completions satisfies the expression.
+	// Work-queue Jobs without completions never match.
+	// +optional
+	Completions string `json:"completions,omitempty"`
+
+	// Parallelism matches Jobs whose parallelism satisfies the expression. An unset parallelism counts as 1.
+	// +optional
+	Parallelism string `json:"parallelism,omitempty"`
+
+	// ActiveDeadlineSeconds matches Jobs whose activeDeadlineSeconds satisfies the expression.
+	// Jobs without a deadline never match.
+	// +optional
+	ActiveDeadlineSeconds string `json:"activeDeadlineSeconds,omitempty"`
+
+	// TTLSecondsAfterFinished matches Jobs whose ttlSecondsAfterFinished satisfies the expression.
+	// Jobs that are never cleaned up automatically never match.
+	// +optional
+	TTLSecondsAfterFinished string `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Suspend matches suspended (true) or unsuspended (false) Jobs.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// Status matches Jobs by outcome: Complete and Failed follow the Job's conditions, Active covers
+	// every Job that has not finished yet. Not available for CronJob targets.
+	// +optional
+	// +kubebuilder:validation:Enum=Active;Complete;Failed
+	Status string `json:"status,omitempty"`
+}
+
+// CronJobMatchCriteria contains CronJob-specific match criteria
+type CronJobMatchCriteria struct {
+	// Schedule matches CronJobs whose schedule equals the given cron expression, ignoring differences in
+	// whitespace (e.g., "@hourly", "*/5 * * * *"). Wildcards are not supported as * is part of cron syntax.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// ScheduleInterval matches CronJobs by the shortest interval between two consecutive runs of their schedule.
+	// Supports comparison operators and ranges over durations (e.g., "<1h" for CronJobs running more often
+	// than hourly, ">=24h" for daily or rarer ones).
+	// +optional
+	ScheduleInterval string `json:"scheduleInterval,omitempty"`
+
+	// ConcurrencyPolicy matches CronJobs with the given concurrency policy.
+	// Valid values: Allow, Forbid, Replace
+	// +optional
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend matches suspended (true) or active (false) CronJobs.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+}
+
+// FieldRequirement matches a field of the target object selected by a JSONPath-style path.
+type FieldRequirement struct {
+	// Path selects the field, e.g. "spec.priorityClassName", ".spec.containers[*].image" or
+	// "{.metadata.ownerReferences[0].kind}". The surrounding braces and the leading dot are optional.
+	// Lists selected without an index contribute each of their elements.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// Operator is applied to each value the path selects:
+	// Equals compares the string form of the value with Value, In with any of Values,
+	// Regex matches the whole value against the regular expression in Value,
+	// Numeric and Quantity compare numbers or resource quantities with a comparison expression in Value
+	// (e.g. ">=3", "1..5", "<=512Mi").
+	// Exists and DoesNotExist only check whether the path selects anything.
+	// +kubebuilder:validation:Enum=Equals;In;Exists;DoesNotExist;Regex;Numeric;Quantity
+	// +kubebuilder:default=Equals
+	// +optional
+	Operator string `json:"operator,omitempty"`
+
+	// Value is the operand of the Equals, Regex, Numeric and Quantity operators.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Values are the accepted values of the In operator.
+	// +optional
+	// +listType=atomic
+	Values []string `json:"values,omitempty"`
+
+	// Match decides how a path that selects several values is evaluated: Any requires one value to satisfy
+	// the operator, All requires every value to. A path that selects nothing never matches either way.
+	// +kubebuilder:validation:Enum=Any;All
+	// +kubebuilder:default=Any
+	// +optional
+	Match string `json:"match,omitempty"`
+}
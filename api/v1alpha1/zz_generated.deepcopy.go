@@ -125,6 +125,11 @@ func (in *ClassificationRuleStatus) DeepCopyInto(out *ClassificationRuleStatus)
 		in, out := &in.LastReconciled, &out.LastReconciled
 		*out = (*in).DeepCopy()
 	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]LabelConflict, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClassificationRuleStatus.
@@ -154,6 +159,25 @@ func (in *CommonMatchCriteria) DeepCopyInto(out *CommonMatchCriteria) {
 			(*out)[key] = val
 		}
 	}
+	if in.LabelRequirements != nil {
+		in, out := &in.LabelRequirements, &out.LabelRequirements
+		*out = make([]MetadataRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnnotationRequirements != nil {
+		in, out := &in.AnnotationRequirements, &out.AnnotationRequirements
+		*out = make([]MetadataRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonMatchCriteria.
@@ -166,6 +190,53 @@ func (in *CommonMatchCriteria) DeepCopy() *CommonMatchCriteria {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronJobMatchCriteria) DeepCopyInto(out *CronJobMatchCriteria) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronJobMatchCriteria.
+func (in *CronJobMatchCriteria) DeepCopy() *CronJobMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(CronJobMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetMatchCriteria) DeepCopyInto(out *DaemonSetMatchCriteria) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetMatchCriteria.
+func (in *DaemonSetMatchCriteria) DeepCopy() *DaemonSetMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentMatchCriteria) DeepCopyInto(out *DeploymentMatchCriteria) {
 	*out = *in
@@ -181,6 +252,61 @@ func (in *DeploymentMatchCriteria) DeepCopy() *DeploymentMatchCriteria {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldRequirement) DeepCopyInto(out *FieldRequirement) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldRequirement.
+func (in *FieldRequirement) DeepCopy() *FieldRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobMatchCriteria) DeepCopyInto(out *JobMatchCriteria) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobMatchCriteria.
+func (in *JobMatchCriteria) DeepCopy() *JobMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(JobMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelConflict) DeepCopyInto(out *LabelConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelConflict.
+func (in *LabelConflict) DeepCopy() *LabelConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchCriteria) DeepCopyInto(out *MatchCriteria) {
 	*out = *in
@@ -204,6 +330,67 @@ func (in *MatchCriteria) DeepCopyInto(out *MatchCriteria) {
 		*out = new(DeploymentMatchCriteria)
 		**out = **in
 	}
+	if in.NamespaceMatch != nil {
+		in, out := &in.NamespaceMatch, &out.NamespaceMatch
+		*out = new(NamespaceMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMatch != nil {
+		in, out := &in.ServiceMatch, &out.ServiceMatch
+		*out = new(ServiceMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatefulSetMatch != nil {
+		in, out := &in.StatefulSetMatch, &out.StatefulSetMatch
+		*out = new(StatefulSetMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DaemonSetMatch != nil {
+		in, out := &in.DaemonSetMatch, &out.DaemonSetMatch
+		*out = new(DaemonSetMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaSetMatch != nil {
+		in, out := &in.ReplicaSetMatch, &out.ReplicaSetMatch
+		*out = new(ReplicaSetMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobMatch != nil {
+		in, out := &in.JobMatch, &out.JobMatch
+		*out = new(JobMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CronJobMatch != nil {
+		in, out := &in.CronJobMatch, &out.CronJobMatch
+		*out = new(CronJobMatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FieldMatch != nil {
+		in, out := &in.FieldMatch, &out.FieldMatch
+		*out = make([]FieldRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]MatchCriteria, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]MatchCriteria, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Not != nil {
+		in, out := &in.Not, &out.Not
+		*out = new(MatchCriteria)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchCriteria.
@@ -216,6 +403,51 @@ func (in *MatchCriteria) DeepCopy() *MatchCriteria {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataRequirement) DeepCopyInto(out *MetadataRequirement) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataRequirement.
+func (in *MetadataRequirement) DeepCopy() *MetadataRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMatchCriteria) DeepCopyInto(out *NamespaceMatchCriteria) {
+	*out = *in
+	if in.HasLabels != nil {
+		in, out := &in.HasLabels, &out.HasLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HasResourceQuota != nil {
+		in, out := &in.HasResourceQuota, &out.HasResourceQuota
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMatchCriteria.
+func (in *NamespaceMatchCriteria) DeepCopy() *NamespaceMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMatchCriteria) DeepCopyInto(out *NodeMatchCriteria) {
 	*out = *in
@@ -229,11 +461,65 @@ func (in *NodeMatchCriteria) DeepCopyInto(out *NodeMatchCriteria) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LegacyArchLabels != nil {
+		in, out := &in.LegacyArchLabels, &out.LegacyArchLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LegacyOSLabels != nil {
+		in, out := &in.LegacyOSLabels, &out.LegacyOSLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Unschedulable != nil {
+		in, out := &in.Unschedulable, &out.Unschedulable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderIDPrefixes != nil {
+		in, out := &in.ProviderIDPrefixes, &out.ProviderIDPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Taints != nil {
 		in, out := &in.Taints, &out.Taints
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMatchCriteria.
@@ -266,6 +552,16 @@ func (in *PodMatchCriteria) DeepCopyInto(out *PodMatchCriteria) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(PodSecurityCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(PodStatusCriteria)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMatchCriteria.
@@ -277,3 +573,195 @@ func (in *PodMatchCriteria) DeepCopy() *PodMatchCriteria {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityCriteria) DeepCopyInto(out *PodSecurityCriteria) {
+	*out = *in
+	if in.Privileged != nil {
+		in, out := &in.Privileged, &out.Privileged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AddedCapabilities != nil {
+		in, out := &in.AddedCapabilities, &out.AddedCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowPrivilegeEscalation != nil {
+		in, out := &in.AllowPrivilegeEscalation, &out.AllowPrivilegeEscalation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunAsNonRoot != nil {
+		in, out := &in.RunAsNonRoot, &out.RunAsNonRoot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunAsRoot != nil {
+		in, out := &in.RunAsRoot, &out.RunAsRoot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SeccompProfileTypes != nil {
+		in, out := &in.SeccompProfileTypes, &out.SeccompProfileTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppArmorProfileTypes != nil {
+		in, out := &in.AppArmorProfileTypes, &out.AppArmorProfileTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostPID != nil {
+		in, out := &in.HostPID, &out.HostPID
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostIPC != nil {
+		in, out := &in.HostIPC, &out.HostIPC
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostPath != nil {
+		in, out := &in.HostPath, &out.HostPath
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostPathPrefixes != nil {
+		in, out := &in.HostPathPrefixes, &out.HostPathPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityCriteria.
+func (in *PodSecurityCriteria) DeepCopy() *PodSecurityCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodStatusCriteria) DeepCopyInto(out *PodStatusCriteria) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.QOSClasses != nil {
+		in, out := &in.QOSClasses, &out.QOSClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ready != nil {
+		in, out := &in.Ready, &out.Ready
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodStatusCriteria.
+func (in *PodStatusCriteria) DeepCopy() *PodStatusCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(PodStatusCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaSetMatchCriteria) DeepCopyInto(out *ReplicaSetMatchCriteria) {
+	*out = *in
+	if in.HasOwner != nil {
+		in, out := &in.HasOwner, &out.HasOwner
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaSetMatchCriteria.
+func (in *ReplicaSetMatchCriteria) DeepCopy() *ReplicaSetMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaSetMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMatchCriteria) DeepCopyInto(out *ServiceMatchCriteria) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Headless != nil {
+		in, out := &in.Headless, &out.Headless
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMatchCriteria.
+func (in *ServiceMatchCriteria) DeepCopy() *ServiceMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetMatchCriteria) DeepCopyInto(out *StatefulSetMatchCriteria) {
+	*out = *in
+	if in.HasVolumeClaimTemplates != nil {
+		in, out := &in.HasVolumeClaimTemplates, &out.HasVolumeClaimTemplates
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetMatchCriteria.
+func (in *StatefulSetMatchCriteria) DeepCopy() *StatefulSetMatchCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetMatchCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
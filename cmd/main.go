@@ -37,6 +37,8 @@ import (
 
 	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
 	"github.com/Joe-Bresee/Autolabeller/internal/controller"
+	webhookv1 "github.com/Joe-Bresee/Autolabeller/internal/webhook/v1"
+	webhookv1alpha1 "github.com/Joe-Bresee/Autolabeller/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -185,6 +187,17 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ClassificationRule")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1.SetupPodWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupClassificationRuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClassificationRule")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -19,26 +19,53 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
 	"github.com/Joe-Bresee/Autolabeller/internal/controller/helpers"
 	"github.com/Joe-Bresee/Autolabeller/internal/controller/matchinglogic"
 )
 
+// classificationRuleFinalizer makes sure the labels a rule applied are removed before the rule goes away.
+const classificationRuleFinalizer = "autolabeller.github.com/label-cleanup"
+
+// unresolvedTargetRetryInterval is how often a rule whose target kind is not served is retried, as installing
+// the kind's CRD does not trigger any event the rule watches.
+const unresolvedTargetRetryInterval = time.Minute
+
 // ClassificationRuleReconciler reconciles a ClassificationRule object
 type ClassificationRuleReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// controller and cache are used to add watches for target kinds without typed support on demand
+	controller   controller.Controller
+	cache        cache.Cache
+	watchMu      sync.Mutex
+	watchedKinds map[schema.GroupVersionKind]struct{}
 }
 
 // +kubebuilder:rbac:groups=autolabeller.autolabeller.github.com,resources=classificationrules,verbs=get;list;watch;create;update;patch;delete
@@ -47,6 +74,11 @@ type ClassificationRuleReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets;daemonsets;replicasets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -65,6 +97,33 @@ func (r *ClassificationRuleReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	// Remove owned labels before letting a deleted rule go
+	if !rule.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&rule, classificationRuleFinalizer) {
+			skipped, err := r.removeOwnedLabels(ctx, &rule)
+			if err != nil {
+				helpers.SetConditionWithLog(log, &rule, "Degraded", metav1.ConditionTrue, "CleanupFailed", fmt.Sprintf("Failed to remove owned labels: %v", err))
+				_ = r.Status().Update(ctx, &rule)
+				return ctrl.Result{}, err
+			}
+			if len(skipped) > 0 {
+				helpers.SetConditionWithLog(log, &rule, "Degraded", metav1.ConditionTrue, "CleanupSkipped",
+					fmt.Sprintf("Owned labels were left on %s targets the controller is not allowed to list", strings.Join(skipped, ", ")))
+				_ = r.Status().Update(ctx, &rule)
+			}
+			controllerutil.RemoveFinalizer(&rule, classificationRuleFinalizer)
+			if err := r.Update(ctx, &rule); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if controllerutil.AddFinalizer(&rule, classificationRuleFinalizer) {
+		if err := r.Update(ctx, &rule); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Guard suspend
 	if rule.Spec.Suspend {
 		helpers.SetConditionWithLog(log, &rule, "Suspended", metav1.ConditionTrue, "RuleSuspended", "Rule is suspended")
@@ -72,102 +131,116 @@ func (r *ClassificationRuleReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, nil
 	}
 
-	matched := int32(0)
-	switch rule.Spec.TargetKind {
-	case "Pod":
-		var pods corev1.PodList
-		listOpts := []client.ListOption{}
-		if rule.Spec.Match != nil && rule.Spec.Match.CommonMatch != nil {
-			// filter listing options
-			helpers.FilterPodList(&listOpts, rule.Spec.Match)
-		}
-		if err := r.List(ctx, &pods, listOpts...); err != nil {
-			helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "ListFailed", fmt.Sprintf("Failed to list pods: %v", err))
-			_ = r.Status().Update(ctx, &rule)
-			return ctrl.Result{}, err
-		}
-		for i := range pods.Items {
-			pod := &pods.Items[i]
-			if ok, fields := matchinglogic.MatchesPodDetailed(rule.Spec.Match, pod); ok {
-				if helpers.ApplyLabelsToObject(pod, rule.Spec.Labels) {
-					log.Info("pod matched criteria, applying labels", "pod", client.ObjectKeyFromObject(pod), "matchedFields", fields)
-					if err := r.Update(ctx, pod); err != nil {
-						helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "UpdateFailed", fmt.Sprintf("Failed to update pod labels: %v", err))
-						_ = r.Status().Update(ctx, &rule)
-						continue
-					}
-					matched++
-				}
-			}
+	gvk := ruleTargetGVK(&rule)
+	kind := gvk.Kind
+	clusterScoped, err := r.targetScope(gvk)
+	if err != nil {
+		helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "UnsupportedTarget", fmt.Sprintf("Cannot resolve target %s: %v", gvk, err))
+		_ = r.Status().Update(ctx, &rule)
+		switch {
+		case meta.IsNoMatchError(err):
+			// The kind may be served later, e.g. once its CRD is installed
+			return ctrl.Result{RequeueAfter: unresolvedTargetRetryInterval}, nil
+		case gvk.Version == "":
+			// Retrying cannot help until the spec names an apiVersion
+			return ctrl.Result{}, nil
 		}
-	case "Node":
-		var nodes corev1.NodeList
-		listOpts := []client.ListOption{}
-		if rule.Spec.Match != nil && rule.Spec.Match.CommonMatch != nil && rule.Spec.Match.CommonMatch.Namespace != "" {
-			helpers.SetConditionWithLog(log, &rule, "Degraded", metav1.ConditionTrue, "NamespaceIgnoredForNode", "commonMatch.namespace is ignored for Node targetKind")
+		return ctrl.Result{}, err
+	}
+	if !checkMatchCriteria(log, &rule) {
+		_ = r.Status().Update(ctx, &rule)
+		return ctrl.Result{}, nil
+	}
+	if err := r.ensureTargetWatch(gvk); err != nil {
+		helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "WatchFailed", fmt.Sprintf("Failed to watch %s targets: %v", gvk, err))
+		_ = r.Status().Update(ctx, &rule)
+		return ctrl.Result{}, err
+	}
+	if clusterScoped && rule.Spec.Match != nil && rule.Spec.Match.CommonMatch != nil && rule.Spec.Match.CommonMatch.Namespace != "" {
+		helpers.SetConditionWithLog(log, &rule, "Degraded", metav1.ConditionTrue, "NamespaceIgnoredFor"+kind, fmt.Sprintf("commonMatch.namespace is ignored for %s targetKind", kind))
+	}
+
+	// List candidates, narrowed server-side by the rule's pre-filters
+	objs, err := r.listTargets(ctx, gvk, targetListOptions(gvk, clusterScoped, rule.Spec.Match)...)
+	if err != nil {
+		helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "ListFailed", fmt.Sprintf("Failed to list %s targets: %v", kind, err))
+		_ = r.Status().Update(ctx, &rule)
+		return ctrl.Result{}, err
+	}
+	env, err := r.loadEnvironment(ctx, rule.Spec.Match)
+	if err != nil {
+		helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "ListFailed", fmt.Sprintf("Failed to load match context: %v", err))
+		_ = r.Status().Update(ctx, &rule)
+		return ctrl.Result{}, err
+	}
+
+	owner := helpers.RuleOwnerKey(&rule)
+	matched := int32(0)
+	updated := 0
+	matchedKeys := map[client.ObjectKey]struct{}{}
+	var allConflicts []autolabellerv1alpha1.LabelConflict
+	var recheckAfter time.Duration
+	var updateErrs []error
+	for _, obj := range objs {
+		switch target := obj.(type) {
+		case *corev1.Pod:
+			recheckAfter = matchinglogic.Sooner(recheckAfter, matchinglogic.PodRecheckAfter(rule.Spec.Match, target, time.Now()))
+		case *corev1.Namespace:
+			recheckAfter = matchinglogic.Sooner(recheckAfter, matchinglogic.NamespaceRecheckAfter(rule.Spec.Match, target, time.Now()))
 		}
-		if rule.Spec.Match != nil && rule.Spec.Match.NodeMatch != nil {
-			helpers.FilterNodeList(&listOpts, rule.Spec.Match)
+		ok, fields := matchTarget(rule.Spec.Match, obj, env)
+		if !ok {
+			continue
 		}
-		if err := r.List(ctx, &nodes, listOpts...); err != nil {
-			helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "ListFailed", fmt.Sprintf("Failed to list nodes: %v", err))
-			_ = r.Status().Update(ctx, &rule)
-			return ctrl.Result{}, err
+		matched++
+		matchedKeys[client.ObjectKeyFromObject(obj)] = struct{}{}
+		changed, conflicts := helpers.ApplyLabelsToObject(obj, owner, rule.Spec.Labels, rule.Spec.ConflictPolicy)
+		allConflicts = append(allConflicts, logConflicts(log, kind, conflicts)...)
+		if !changed {
+			continue
 		}
-		for i := range nodes.Items {
-			node := &nodes.Items[i]
-			if ok, fields := matchinglogic.MatchesNodeDetailed(rule.Spec.Match, node); ok {
-				if helpers.ApplyLabelsToObject(node, rule.Spec.Labels) {
-					log.Info("node matched criteria, applying labels", "node", client.ObjectKeyFromObject(node), "matchedFields", fields)
-					if err := r.Update(ctx, node); err != nil {
-						helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "UpdateFailed", fmt.Sprintf("Failed to update node labels: %v", err))
-						_ = r.Status().Update(ctx, &rule)
-						continue
-					}
-					matched++
-				}
+		log.Info("object matched criteria, applying labels", "kind", kind, "object", client.ObjectKeyFromObject(obj), "matchedFields", fields)
+		if err := r.Update(ctx, obj); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
 			}
+			log.Error(err, "failed to update labels", "kind", kind, "object", client.ObjectKeyFromObject(obj))
+			updateErrs = append(updateErrs, fmt.Errorf("failed to update %s %s: %w", kind, client.ObjectKeyFromObject(obj), err))
+			continue
 		}
-	case "Deployment":
-		var deployments appsv1.DeploymentList
-		listOpts := []client.ListOption{}
-		if rule.Spec.Match != nil && rule.Spec.Match.CommonMatch != nil {
-			helpers.FilterPodList(&listOpts, rule.Spec.Match)
-		}
-		if err := r.List(ctx, &deployments, listOpts...); err != nil {
-			helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "ListFailed", fmt.Sprintf("Failed to list deployments: %v", err))
+		updated++
+	}
+
+	// The rule's labels are desired state: withdraw them from targets that no longer match, unless sticky
+	withdrawn := 0
+	if !rule.Spec.Sticky {
+		withdrawn, err = r.withdrawUnmatched(ctx, &rule, matchedKeys)
+		if err != nil {
+			helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "WithdrawFailed", fmt.Sprintf("Failed to withdraw labels from unmatched %s targets: %v", kind, err))
 			_ = r.Status().Update(ctx, &rule)
 			return ctrl.Result{}, err
 		}
-		for i := range deployments.Items {
-			deployment := &deployments.Items[i]
-			if ok, fields := matchinglogic.MatchesDeploymentDetailed(rule.Spec.Match, deployment); ok {
-				if helpers.ApplyLabelsToObject(deployment, rule.Spec.Labels) {
-					log.Info("deployment matched criteria, applying labels", "deployment", client.ObjectKeyFromObject(deployment), "matchedFields", fields)
-					if err := r.Update(ctx, deployment); err != nil {
-						helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "UpdateFailed", fmt.Sprintf("Failed to update deployment labels: %v", err))
-						_ = r.Status().Update(ctx, &rule)
-						continue
-					}
-					matched++
-				}
-			}
-		}
-	default:
-		helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "UnsupportedTarget", fmt.Sprintf("TargetKind %s not yet implemented", rule.Spec.TargetKind))
-		_ = r.Status().Update(ctx, &rule)
-		return ctrl.Result{}, nil
 	}
 
 	rule.Status.MatchedResourcesCount = matched
+	setConflictStatus(log, &rule, allConflicts)
 	rule.Status.ObservedGeneration = rule.GetGeneration()
-	helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionTrue, "Applied", fmt.Sprintf("Applied labels to %d resources", matched))
+	// Failed updates are retried with backoff; the rule is not reported as applied until every target is labelled.
+	// The condition and the returned error both summarise the failures by the first one; each is logged above.
+	if len(updateErrs) > 0 {
+		summary := fmt.Sprintf("%d of %d matching %s targets, first error: %v", len(updateErrs), matched, kind, updateErrs[0])
+		helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionFalse, "UpdateFailed", "Failed to update labels on "+summary)
+		_ = r.Status().Update(ctx, &rule)
+		return ctrl.Result{}, fmt.Errorf("failed to update labels on %s", summary)
+	}
+	helpers.SetConditionWithLog(log, &rule, "Ready", metav1.ConditionTrue, "Applied",
+		fmt.Sprintf("Matched %d resources, updated labels on %d, withdrew labels from %d", matched, updated, withdrawn))
 	if err := r.Status().Update(ctx, &rule); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Compute reqeue Interval
-	requeueAfter := 30 * time.Second // default
+	// Compute the optional safety resync; target events drive reconciliation otherwise
+	var requeueAfter time.Duration
 	if rule.Spec.RefreshInterval != "" {
 		d, err := time.ParseDuration(rule.Spec.RefreshInterval)
 		if err != nil {
@@ -177,15 +250,256 @@ func (r *ClassificationRuleReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 		requeueAfter = d
 	}
+	// Time-based criteria, such as how long a pod has been pending or a namespace's age, change without a target event
+	requeueAfter = matchinglogic.Sooner(requeueAfter, recheckAfter)
 
 	log.Info("Reconcile completed", "requeueAfter", requeueAfter.String())
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
+// withdrawUnmatched removes the labels owned by rule from targets that are not in matched. Every kind the rule may
+// have labelled is swept, not only the current TargetKind, so labels applied before a TargetKind change are withdrawn
+// as well; matched only covers the current TargetKind. Kinds that are no longer served or that the controller is not
+// allowed to list are skipped. It returns the number of targets that were updated.
+func (r *ClassificationRuleReconciler) withdrawUnmatched(ctx context.Context, rule *autolabellerv1alpha1.ClassificationRule, matched map[client.ObjectKey]struct{}) (int, error) {
+	log := logf.FromContext(ctx)
+	owner := helpers.RuleOwnerKey(rule)
+	current := ruleTargetGVK(rule)
+	withdrawn := 0
+	for _, gvk := range ownedLabelGVKs(rule) {
+		kind := gvk.Kind
+		objs, err := r.listTargets(ctx, gvk)
+		if meta.IsNoMatchError(err) || kerrors.IsForbidden(err) {
+			// Labels on kinds the controller cannot see are left in place until the rule is deleted
+			continue
+		}
+		if err != nil {
+			return withdrawn, fmt.Errorf("failed to list %s targets: %w", kind, err)
+		}
+		for _, obj := range objs {
+			if _, ok := matched[client.ObjectKeyFromObject(obj)]; ok && gvk == current {
+				continue
+			}
+			if !helpers.RemoveOwnedLabels(obj, owner) {
+				continue
+			}
+			log.Info("object no longer matches criteria, withdrawing labels", "kind", kind, "object", client.ObjectKeyFromObject(obj))
+			if err := r.Update(ctx, obj); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return withdrawn, fmt.Errorf("failed to update %s %s: %w", kind, client.ObjectKeyFromObject(obj), err)
+			}
+			withdrawn++
+		}
+	}
+	return withdrawn, nil
+}
+
+// ownedLabelGVKs returns the kinds that may carry labels owned by rule: every built-in target kind, so labels applied
+// before a TargetKind change are found as well, and the current TargetKind. Kinds without typed support are only
+// visited while the rule targets them.
+func ownedLabelGVKs(rule *autolabellerv1alpha1.ClassificationRule) []schema.GroupVersionKind {
+	gvks := make([]schema.GroupVersionKind, 0, len(supportedTargetKinds)+1)
+	for _, kind := range supportedTargetKinds {
+		gvks = append(gvks, targetGVK("", kind))
+	}
+	if gvk := ruleTargetGVK(rule); !isBuiltinGVK(gvk) && gvk.Version != "" {
+		gvks = append(gvks, gvk)
+	}
+	return gvks
+}
+
+// removeOwnedLabels strips the labels owned by rule from every kind returned by ownedLabelGVKs. Kinds the controller
+// is not allowed to list are skipped and returned, so a missing RBAC grant cannot keep the rule from being deleted.
+func (r *ClassificationRuleReconciler) removeOwnedLabels(ctx context.Context, rule *autolabellerv1alpha1.ClassificationRule) ([]string, error) {
+	log := logf.FromContext(ctx)
+	owner := helpers.RuleOwnerKey(rule)
+	var skipped []string
+	for _, gvk := range ownedLabelGVKs(rule) {
+		kind := gvk.Kind
+		objs, err := r.listTargets(ctx, gvk)
+		if meta.IsNoMatchError(err) {
+			// The kind is no longer served, so no object can carry the rule's labels
+			continue
+		}
+		if kerrors.IsForbidden(err) {
+			log.Error(err, "not allowed to list targets, leaving their owned labels in place", "kind", kind)
+			skipped = append(skipped, kind)
+			continue
+		}
+		if err != nil {
+			return skipped, fmt.Errorf("failed to list %s targets: %w", kind, err)
+		}
+		for _, obj := range objs {
+			if !helpers.RemoveOwnedLabels(obj, owner) {
+				continue
+			}
+			log.Info("rule deleted, removing owned labels", "kind", kind, "object", client.ObjectKeyFromObject(obj))
+			if err := r.Update(ctx, obj); err != nil && !kerrors.IsNotFound(err) {
+				return skipped, fmt.Errorf("failed to update %s %s: %w", kind, client.ObjectKeyFromObject(obj), err)
+			}
+		}
+	}
+	return skipped, nil
+}
+
+// logConflicts logs the conflict policy decision taken for each conflicting label and returns the conflicts unchanged.
+func logConflicts(log logr.Logger, kind string, conflicts []autolabellerv1alpha1.LabelConflict) []autolabellerv1alpha1.LabelConflict {
+	for _, c := range conflicts {
+		log.Info("label conflict", "kind", kind, "object", types.NamespacedName{Namespace: c.ResourceNamespace, Name: c.ResourceName},
+			"key", c.Key, "existingValue", c.ExistingValue, "desiredValue", c.DesiredValue, "resolution", c.Resolution)
+	}
+	return conflicts
+}
+
+// setConflictStatus records the conflicts of this pass in the rule status. Conflicts rejected by the
+// Error policy mark the rule as Degraded until a pass completes without any.
+func setConflictStatus(log logr.Logger, rule *autolabellerv1alpha1.ClassificationRule, conflicts []autolabellerv1alpha1.LabelConflict) {
+	rejected := 0
+	for _, c := range conflicts {
+		if c.Resolution == helpers.ConflictResolutionRejected {
+			rejected++
+		}
+	}
+	if len(conflicts) > helpers.MaxReportedConflicts {
+		conflicts = conflicts[:helpers.MaxReportedConflicts]
+	}
+	rule.Status.Conflicts = conflicts
+
+	if rejected > 0 {
+		msg := fmt.Sprintf("Refused to label %d conflicting label(s) under ConflictPolicy Error", rejected)
+		rule.Status.LastError = msg
+		helpers.SetConditionWithLog(log, rule, "Degraded", metav1.ConditionTrue, "LabelConflict", msg)
+		return
+	}
+	if cond := meta.FindStatusCondition(rule.Status.Conditions, "Degraded"); cond != nil && cond.Reason == "LabelConflict" {
+		rule.Status.LastError = ""
+		helpers.SetConditionWithLog(log, rule, "Degraded", metav1.ConditionFalse, "NoConflicts", "No label conflicts rejected")
+	}
+}
+
+// checkMatchCriteria validates the rule's match criteria before they are evaluated. Malformed criteria would match
+// nothing and withdraw every label the rule owns, so the rule is marked Degraded and not applied until it is fixed.
+// It returns whether the criteria are valid.
+func checkMatchCriteria(log logr.Logger, rule *autolabellerv1alpha1.ClassificationRule) bool {
+	errs := matchinglogic.ValidateMatchCriteria(rule.Spec.TargetAPIVersion, rule.Spec.TargetKind, rule.Spec.Match, field.NewPath("spec", "match"))
+	if len(errs) > 0 {
+		msg := fmt.Sprintf("Invalid match criteria: %v", errs.ToAggregate())
+		rule.Status.LastError = msg
+		helpers.SetConditionWithLog(log, rule, "Degraded", metav1.ConditionTrue, "InvalidMatchExpression", msg)
+		helpers.SetConditionWithLog(log, rule, "Ready", metav1.ConditionFalse, "InvalidMatchExpression", "Rule is not applied until its match criteria are fixed")
+		return false
+	}
+	if cond := meta.FindStatusCondition(rule.Status.Conditions, "Degraded"); cond != nil && cond.Reason == "InvalidMatchExpression" {
+		rule.Status.LastError = ""
+		helpers.SetConditionWithLog(log, rule, "Degraded", metav1.ConditionFalse, "ValidMatchExpression", "Match criteria are valid")
+	}
+	return true
+}
+
+// rulesForTarget returns a map function that enqueues the rules targeting gvk which either match the changed object
+// or own labels on it, so newly created targets get labelled and targets that stop matching get their labels withdrawn.
+func (r *ClassificationRuleReconciler) rulesForTarget(gvk schema.GroupVersionKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var rules autolabellerv1alpha1.ClassificationRuleList
+		if err := r.List(ctx, &rules); err != nil {
+			logf.FromContext(ctx).Error(err, "failed to list ClassificationRules for target event", "kind", gvk, "object", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+		own := helpers.GetLabelOwnership(obj)
+		var requests []reconcile.Request
+		for i := range rules.Items {
+			rule := &rules.Items[i]
+			if ruleTargetGVK(rule) != gvk || rule.Spec.Suspend {
+				continue
+			}
+			_, owned := own[helpers.RuleOwnerKey(rule)]
+			if !owned {
+				env, err := r.loadEnvironment(ctx, rule.Spec.Match)
+				if err != nil {
+					logf.FromContext(ctx).Error(err, "failed to load match context, enqueueing rule", "rule", client.ObjectKeyFromObject(rule))
+				} else if ok, _ := matchTarget(rule.Spec.Match, obj, env); !ok {
+					continue
+				}
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+		}
+		return requests
+	}
+}
+
+// rulesForResourceQuota enqueues the Namespace rules that depend on ResourceQuota presence when a quota
+// is created or deleted, since that changes whether its namespace matches.
+func (r *ClassificationRuleReconciler) rulesForResourceQuota(ctx context.Context, obj client.Object) []reconcile.Request {
+	var rules autolabellerv1alpha1.ClassificationRuleList
+	if err := r.List(ctx, &rules); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list ClassificationRules for ResourceQuota event", "object", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		if ruleTargetGVK(rule) != corev1.SchemeGroupVersion.WithKind("Namespace") || rule.Spec.Suspend || !matchinglogic.NeedsQuotaNamespaces(rule.Spec.Match) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+	}
+	return requests
+}
+
+// rulesForNamespaceLabels enqueues the rules with a namespaceSelector when a namespace's labels change, since that
+// changes which namespaced targets they match. Rules for cluster-scoped kinds ignore namespaceSelector.
+func (r *ClassificationRuleReconciler) rulesForNamespaceLabels(ctx context.Context, obj client.Object) []reconcile.Request {
+	var rules autolabellerv1alpha1.ClassificationRuleList
+	if err := r.List(ctx, &rules); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list ClassificationRules for Namespace event", "object", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		gvk := ruleTargetGVK(rule)
+		if rule.Spec.Suspend || !matchinglogic.NeedsNamespaceLabels(rule.Spec.Match) || (isBuiltinGVK(gvk) && isClusterScopedKind(gvk.Kind)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
+// Rules are reconciled when their spec changes and whenever an object of a supported TargetKind changes;
+// RefreshInterval only adds an optional periodic resync on top. Kinds without typed support are watched once a
+// rule targets them, see ensureTargetWatch.
 func (r *ClassificationRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&autolabellerv1alpha1.ClassificationRule{}).
-		Named("classificationrule").
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&autolabellerv1alpha1.ClassificationRule{}, builder.WithPredicates(ruleSpecChanged))
+	for _, kind := range supportedTargetKinds {
+		b = b.Watches(newTargetObject(kind), handler.EnqueueRequestsFromMapFunc(r.rulesForTarget(targetGVK("", kind))))
+	}
+	b = b.Watches(&corev1.ResourceQuota{}, handler.EnqueueRequestsFromMapFunc(r.rulesForResourceQuota),
+		builder.WithPredicates(quotaCreatedOrDeleted))
+	// Namespaces are watched as targets above; label changes also affect rules selecting targets by namespace labels
+	b = b.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.rulesForNamespaceLabels),
+		builder.WithPredicates(predicate.LabelChangedPredicate{}))
+	c, err := b.Named("classificationrule").
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
+	r.cache = mgr.GetCache()
+	return nil
 }
+
+// ruleSpecChanged reconciles rules when they are created, deleted or their spec changes; status updates written by
+// the reconciler itself do not bump the generation and are ignored. Deleting a rule that still carries the
+// finalizer is an update, not a delete event, and it still passes: the API server bumps metadata.generation when it
+// sets deletionTimestamp, so the finalizer's label cleanup runs. A predicate that does not key on generation, such
+// as ResourceVersionChangedPredicate, would change which of these events are seen.
+var ruleSpecChanged = predicate.GenerationChangedPredicate{}
+
+// quotaCreatedOrDeleted ignores ResourceQuota updates: only quota creation and deletion change whether a namespace
+// has one, while usage updates are frequent and irrelevant.
+var quotaCreatedOrDeleted = predicate.Funcs{UpdateFunc: func(event.UpdateEvent) bool { return false }}
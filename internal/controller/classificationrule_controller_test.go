@@ -21,13 +21,24 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/helpers"
 )
 
 var _ = Describe("ClassificationRule Controller", func() {
@@ -65,6 +76,18 @@ var _ = Describe("ClassificationRule Controller", func() {
 
 			By("Cleanup the specific resource instance ClassificationRule")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("Reconciling the deleted resource to release the finalizer")
+			controllerReconciler := &ClassificationRuleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
@@ -77,8 +100,248 @@ var _ = Describe("ClassificationRule Controller", func() {
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			By("Checking the cleanup finalizer was added")
+			resource := &autolabellerv1alpha1.ClassificationRule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(classificationRuleFinalizer))
 		})
 	})
 })
+
+// newFakeReconciler returns a reconciler backed by a fake client holding objs, for specs that do not need a
+// running API server. funcs optionally intercepts client calls to inject errors.
+func newFakeReconciler(funcs interceptor.Funcs, objs ...client.Object) *ClassificationRuleReconciler {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(autolabellerv1alpha1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&autolabellerv1alpha1.ClassificationRule{}).
+		WithInterceptorFuncs(funcs).Build()
+	return &ClassificationRuleReconciler{Client: c, Scheme: scheme}
+}
+
+// newPodRule returns a Pod rule in the autolabeller-system namespace that labels pods with app=web.
+func newPodRule(name string, labels map[string]string) *autolabellerv1alpha1.ClassificationRule {
+	return &autolabellerv1alpha1.ClassificationRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "autolabeller-system"},
+		Spec: autolabellerv1alpha1.ClassificationRuleSpec{
+			TargetKind: "Pod",
+			Match: &autolabellerv1alpha1.MatchCriteria{
+				CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Labels: map[string]string{"app": "web"}},
+			},
+			Labels:         labels,
+			ConflictPolicy: "Merge",
+		},
+	}
+}
+
+var _ = Describe("Label withdrawal", func() {
+	var (
+		ctx  context.Context
+		rule *autolabellerv1alpha1.ClassificationRule
+		pod  *corev1.Pod
+	)
+
+	reconcileRule := func(r *ClassificationRuleReconciler) error {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+		return err
+	}
+	currentPod := func(r *ClassificationRuleReconciler) *corev1.Pod {
+		current := &corev1.Pod{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(pod), current)).To(Succeed())
+		return current
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rule = newPodRule("web", map[string]string{"tier": "frontend", "exposed": "true"})
+		// The pod was labelled by the rule earlier and has since stopped matching it
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "api-0",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "api", "team": "payments"},
+		}}
+		helpers.ApplyLabelsToObject(pod, helpers.RuleOwnerKey(rule), rule.Spec.Labels, "Merge")
+	})
+
+	It("should withdraw only the labels the rule owns from targets that stop matching", func() {
+		r := newFakeReconciler(interceptor.Funcs{}, rule, pod)
+		Expect(reconcileRule(r)).To(Succeed())
+		Expect(currentPod(r).Labels).To(Equal(map[string]string{"app": "api", "team": "payments"}))
+		Expect(helpers.GetLabelOwnership(currentPod(r))).To(BeEmpty())
+	})
+
+	It("should keep labels another rule also owns", func() {
+		other := newPodRule("frontend", map[string]string{"tier": "frontend"})
+		helpers.ApplyLabelsToObject(pod, helpers.RuleOwnerKey(other), other.Spec.Labels, "Merge")
+		r := newFakeReconciler(interceptor.Funcs{}, rule, pod)
+		Expect(reconcileRule(r)).To(Succeed())
+
+		current := currentPod(r)
+		Expect(current.Labels).To(Equal(map[string]string{"app": "api", "team": "payments", "tier": "frontend"}))
+		Expect(helpers.GetLabelOwnership(current)).To(Equal(helpers.LabelOwnership{
+			helpers.RuleOwnerKey(other): {"tier": "frontend"},
+		}))
+	})
+
+	It("should keep the labels of sticky rules", func() {
+		rule.Spec.Sticky = true
+		r := newFakeReconciler(interceptor.Funcs{}, rule, pod)
+		Expect(reconcileRule(r)).To(Succeed())
+		Expect(currentPod(r).Labels).To(HaveKeyWithValue("tier", "frontend"))
+		Expect(currentPod(r).Labels).To(HaveKeyWithValue("exposed", "true"))
+	})
+
+	It("should tolerate targets deleted during withdrawal", func() {
+		r := newFakeReconciler(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if _, ok := obj.(*corev1.Pod); ok {
+					return errors.NewNotFound(corev1.Resource("pods"), obj.GetName())
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}, rule, pod)
+		Expect(reconcileRule(r)).To(Succeed())
+
+		updated := &autolabellerv1alpha1.ClassificationRule{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(rule), updated)).To(Succeed())
+		Expect(updated.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", "Ready"), HaveField("Status", metav1.ConditionTrue))))
+	})
+
+	It("should withdraw labels from the previous kind when the targetKind changes", func() {
+		rule.Spec.TargetKind = "Deployment"
+		r := newFakeReconciler(interceptor.Funcs{}, rule, pod)
+		Expect(reconcileRule(r)).To(Succeed())
+		Expect(currentPod(r).Labels).To(Equal(map[string]string{"app": "api", "team": "payments"}))
+		Expect(helpers.GetLabelOwnership(currentPod(r))).To(BeEmpty())
+	})
+
+	It("should let a rule be deleted when its target kind cannot be listed", func() {
+		rule.Spec.TargetAPIVersion = "example.com/v1"
+		rule.Spec.TargetKind = "Widget"
+		rule.Finalizers = []string{classificationRuleFinalizer}
+		rule.DeletionTimestamp = ptr.To(metav1.Now())
+		var recorded *autolabellerv1alpha1.ClassificationRule
+		r := newFakeReconciler(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*unstructured.UnstructuredList); ok {
+					return errors.NewForbidden(schema.GroupResource{Group: "example.com", Resource: "widgets"}, "", nil)
+				}
+				return c.List(ctx, list, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if updated, ok := obj.(*autolabellerv1alpha1.ClassificationRule); ok {
+					recorded = updated.DeepCopy()
+				}
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}, rule, pod)
+		Expect(reconcileRule(r)).To(Succeed())
+
+		Expect(helpers.GetLabelOwnership(currentPod(r))).To(BeEmpty(), "labels on listable kinds are still withdrawn")
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(rule), &autolabellerv1alpha1.ClassificationRule{})).To(
+			MatchError(errors.IsNotFound, "IsNotFound"))
+		Expect(recorded).NotTo(BeNil())
+		Expect(recorded.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", "Degraded"), HaveField("Reason", "CleanupSkipped"))))
+	})
+})
+
+var _ = Describe("Label updates", func() {
+	It("should report failed updates and retry instead of reporting the rule as applied", func() {
+		ctx := context.Background()
+		rule := newPodRule("web", map[string]string{"tier": "frontend"})
+		pods := []client.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+		}
+		r := newFakeReconciler(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if _, ok := obj.(*corev1.Pod); ok {
+					return errors.NewConflict(corev1.Resource("pods"), obj.GetName(), nil)
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}, append(pods, rule)...)
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+		Expect(err).To(MatchError(ContainSubstring("2 of 2 matching Pod targets, first error:")))
+
+		updated := &autolabellerv1alpha1.ClassificationRule{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(rule), updated)).To(Succeed())
+		Expect(updated.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", "Ready"), HaveField("Status", metav1.ConditionFalse), HaveField("Reason", "UpdateFailed"),
+			HaveField("Message", ContainSubstring("2 of 2 matching Pod targets, first error:")))))
+	})
+})
+
+var _ = Describe("Event mapping", func() {
+	ctx := context.Background()
+	requestFor := func(rule *autolabellerv1alpha1.ClassificationRule) reconcile.Request {
+		return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)}
+	}
+
+	It("should enqueue the rules for the target's kind that match it or own labels on it", func() {
+		matching := newPodRule("web", map[string]string{"tier": "frontend"})
+		owning := newPodRule("db", map[string]string{"tier": "db"})
+		owning.Spec.Match.CommonMatch.Labels = map[string]string{"app": "db"}
+		unrelated := newPodRule("cache", map[string]string{"tier": "cache"})
+		unrelated.Spec.Match.CommonMatch.Labels = map[string]string{"app": "cache"}
+		suspended := newPodRule("suspended", map[string]string{"suspended": "true"})
+		suspended.Spec.Suspend = true
+		nodes := newPodRule("nodes", map[string]string{"pool": "general"})
+		nodes.Spec.TargetKind = "Node"
+		nodes.Spec.Match = nil
+		r := newFakeReconciler(interceptor.Funcs{}, matching, owning, unrelated, suspended, nodes)
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", Labels: map[string]string{"app": "web"}}}
+		helpers.ApplyLabelsToObject(pod, helpers.RuleOwnerKey(owning), owning.Spec.Labels, "Merge")
+		Expect(r.rulesForTarget(targetGVK("", "Pod"))(ctx, pod)).To(ConsistOf(requestFor(matching), requestFor(owning)))
+	})
+
+	It("should enqueue only Namespace rules that depend on quotas for quota events", func() {
+		quotas := newPodRule("quotas", map[string]string{"quota": "true"})
+		quotas.Spec.TargetKind = "Namespace"
+		quotas.Spec.Match = &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{HasResourceQuota: ptr.To(true)}}
+		namespaces := newPodRule("namespaces", map[string]string{"seen": "true"})
+		namespaces.Spec.TargetKind = "Namespace"
+		namespaces.Spec.Match = nil
+		r := newFakeReconciler(interceptor.Funcs{}, quotas, namespaces, newPodRule("web", nil))
+
+		quota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "default"}}
+		Expect(r.rulesForResourceQuota(ctx, quota)).To(ConsistOf(requestFor(quotas)))
+	})
+
+	It("should enqueue only rules selecting namespaced targets by namespace labels for namespace label changes", func() {
+		selector := &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "core"}}
+		selecting := newPodRule("core", map[string]string{"tier": "core"})
+		selecting.Spec.Match.CommonMatch.NamespaceSelector = selector
+		nodes := newPodRule("nodes", map[string]string{"pool": "core"})
+		nodes.Spec.TargetKind = "Node"
+		nodes.Spec.Match = &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{NamespaceSelector: selector}}
+		r := newFakeReconciler(interceptor.Funcs{}, selecting, nodes, newPodRule("web", nil))
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"tier": "core"}}}
+		Expect(r.rulesForNamespaceLabels(ctx, ns)).To(ConsistOf(requestFor(selecting)))
+	})
+
+	It("should reconcile rules on spec changes but not on status updates", func() {
+		old := newPodRule("web", nil)
+		old.Generation = 1
+		statusOnly := old.DeepCopy()
+		statusOnly.Status.MatchedResourcesCount = 3
+		specChange := old.DeepCopy()
+		specChange.Generation = 2
+		Expect(ruleSpecChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: statusOnly})).To(BeFalse())
+		Expect(ruleSpecChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: specChange})).To(BeTrue())
+	})
+
+	It("should only map quota creation and deletion", func() {
+		quota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "default"}}
+		Expect(quotaCreatedOrDeleted.Create(event.CreateEvent{Object: quota})).To(BeTrue())
+		Expect(quotaCreatedOrDeleted.Delete(event.DeleteEvent{Object: quota})).To(BeTrue())
+		Expect(quotaCreatedOrDeleted.Update(event.UpdateEvent{ObjectOld: quota, ObjectNew: quota})).To(BeFalse())
+	})
+})
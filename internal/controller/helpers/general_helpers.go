@@ -1,13 +1,17 @@
 package helpers
 
 import (
+	"sort"
+
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/matchinglogic"
 )
 
 func SetCondition(rule *autolabellerv1alpha1.ClassificationRule, condType string, status metav1.ConditionStatus, reason, msg string) {
@@ -39,25 +43,146 @@ func SetConditionWithLog(logger logr.Logger, rule *autolabellerv1alpha1.Classifi
 	logger.Info("condition updated", "type", condType, "status", string(status), "reason", reason, "message", msg)
 }
 
-func ApplyLabelsToObject(obj client.Object, labels map[string]string) bool {
-	if len(labels) == 0 {
-		return false
-	}
+// Conflict resolutions reported in ClassificationRuleStatus.Conflicts.
+const (
+	ConflictResolutionOverwritten = "Overwritten"
+	ConflictResolutionKept        = "Kept"
+	ConflictResolutionSkipped     = "Skipped"
+	ConflictResolutionRejected    = "Rejected"
+)
+
+// MaxReportedConflicts caps the number of conflicts recorded in a rule's status to keep the object small.
+const MaxReportedConflicts = 50
+
+// ApplyLabelsToObject applies labels on behalf of owner (see RuleOwnerKey) according to the rule's conflict policy,
+// recording every label it writes in the OwnershipAnnotation. A conflict is a label key already present on obj with
+// a different value that owner did not apply itself (or that another rule also relies on). It returns whether obj
+// was modified and the conflicts encountered, each annotated with the resolution the policy chose:
+//   - Overwrite: conflicting values are replaced and owner takes over the key.
+//   - Merge (default): only missing keys are added, existing values are kept.
+//   - Ignore: obj is left untouched when any conflict is found.
+//   - Error: obj is left untouched and the conflicts are returned as rejected.
+//
+// Labels that owner applied earlier but are no longer part of labels are withdrawn.
+func ApplyLabelsToObject(obj client.Object, owner string, labels map[string]string, policy string) (bool, []autolabellerv1alpha1.LabelConflict) {
 	m := obj.GetLabels()
 	if m == nil {
 		m = map[string]string{}
 	}
+	own := GetLabelOwnership(obj)
+
+	resolution := ConflictResolutionKept
+	switch policy {
+	case "Overwrite":
+		resolution = ConflictResolutionOverwritten
+	case "Ignore":
+		resolution = ConflictResolutionSkipped
+	case "Error":
+		resolution = ConflictResolutionRejected
+	}
+
+	var conflicts []autolabellerv1alpha1.LabelConflict
+	conflicting := map[string]bool{}
+	for k, v := range labels {
+		current, present := m[k]
+		if !present || current == v {
+			continue
+		}
+		// A value this rule wrote itself may be updated freely, unless another rule relies on it too
+		if own.OwnedBy(owner, k, current) && len(own.Owners(k, current)) == 1 {
+			continue
+		}
+		conflicting[k] = true
+		conflicts = append(conflicts, autolabellerv1alpha1.LabelConflict{
+			ResourceName:      obj.GetName(),
+			ResourceNamespace: obj.GetNamespace(),
+			Key:               k,
+			ExistingValue:     current,
+			DesiredValue:      v,
+			Resolution:        resolution,
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Key < conflicts[j].Key })
+
+	// Ignore and Error never touch a conflicting object
+	if len(conflicts) > 0 && (resolution == ConflictResolutionSkipped || resolution == ConflictResolutionRejected) {
+		return false, conflicts
+	}
+
 	changed := false
 	for k, v := range labels {
-		if m[k] != v {
+		current, present := m[k]
+		switch {
+		case present && current == v:
+			// Share values another rule applied; values set by hand stay foreign so we never remove them
+			if len(own.Owners(k, v)) > 0 {
+				own.claim(owner, k, v)
+			}
+		case conflicting[k] && resolution != ConflictResolutionOverwritten:
+			own.release(owner, k)
+		default:
+			for other := range own {
+				if other != owner {
+					own.release(other, k)
+				}
+			}
 			m[k] = v
+			own.claim(owner, k, v)
+			changed = true
+		}
+	}
+
+	// Withdraw labels this rule applied earlier but no longer wants
+	for k := range own[owner] {
+		if _, ok := labels[k]; !ok && releaseLabel(m, own, owner, k) {
 			changed = true
 		}
 	}
+
 	if changed {
 		obj.SetLabels(m)
 	}
-	return changed
+	if SetLabelOwnership(obj, own) {
+		changed = true
+	}
+	return changed, conflicts
+}
+
+// PrefilterCriteria returns the part of match that the Filter*List functions may push to the API server. Only
+// criteria every matching object satisfies qualify: the top level and, recursively, the commonMatch namespace,
+// labels and label requirements of allOf branches. anyOf and not branches are dropped, as objects failing them can
+// still match the rule.
+func PrefilterCriteria(match *autolabellerv1alpha1.MatchCriteria) *autolabellerv1alpha1.MatchCriteria {
+	if match == nil || (len(match.AnyOf) == 0 && len(match.AllOf) == 0 && match.Not == nil) {
+		return match
+	}
+	prefilter := *match
+	prefilter.AnyOf, prefilter.AllOf, prefilter.Not = nil, nil, nil
+	for i := range match.AllOf {
+		branch := PrefilterCriteria(&match.AllOf[i])
+		if branch.CommonMatch == nil {
+			continue
+		}
+		cm := prefilter.CommonMatch.DeepCopy()
+		if cm == nil {
+			cm = &autolabellerv1alpha1.CommonMatchCriteria{}
+		}
+		// A conflicting namespace or label value leaves nothing to match; keeping either one is still correct
+		if cm.Namespace == "" {
+			cm.Namespace = branch.CommonMatch.Namespace
+		}
+		for k, v := range branch.CommonMatch.Labels {
+			if _, ok := cm.Labels[k]; !ok {
+				if cm.Labels == nil {
+					cm.Labels = map[string]string{}
+				}
+				cm.Labels[k] = v
+			}
+		}
+		cm.LabelRequirements = append(cm.LabelRequirements, branch.CommonMatch.LabelRequirements...)
+		prefilter.CommonMatch = cm
+	}
+	return &prefilter
 }
 
 func FilterPodList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
@@ -72,9 +197,7 @@ func FilterPodList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.Ma
 		if cm.Namespace != "" {
 			*listOpts = append(*listOpts, client.InNamespace(cm.Namespace))
 		}
-		if len(cm.Labels) > 0 {
-			*listOpts = append(*listOpts, client.MatchingLabels(cm.Labels))
-		}
+		filterCommonLabels(listOpts, cm)
 	}
 }
 
@@ -87,13 +210,13 @@ func FilterNodeList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.M
 	// Name patterns are checked later in MatchesNodeDetailed (requires in-memory inspection)
 	// Annotations are checked later in MatchesNodeDetailed (requires in-memory inspection)
 	if cm := match.CommonMatch; cm != nil {
-		if len(cm.Labels) > 0 {
-			*listOpts = append(*listOpts, client.MatchingLabels(cm.Labels))
-		}
+		filterCommonLabels(listOpts, cm)
 	}
 
-	// NodeMatch filters - single or multi-value arch/os label filtering applied at API level
-	// Taints, KernelVersion, ContainerRuntime are checked later in MatchesNodeDetailed (requires in-memory inspection)
+	// NodeMatch filters - single or multi-value arch/os, legacy arch/os, topology and control-plane label filtering
+	// applied at API level
+	// Taints, versions, resources, conditions, unschedulable and providerID are checked later in MatchesNodeDetailed
+	// (requires in-memory inspection)
 	if nm := match.NodeMatch; nm != nil {
 		// Single arch value → exact label selector
 		if len(nm.ArchLabels) == 1 {
@@ -108,23 +231,41 @@ func FilterNodeList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.M
 			}))
 		}
 
-		// Multi-value arch/os → set-based selectors with OR semantics
-		selector := labels.NewSelector()
-		added := false
+		// Multi-value arch/os and the other label lists → set-based selectors with OR semantics
+		var requirements labelRequirements
 		if len(nm.ArchLabels) > 1 {
 			if req, err := labels.NewRequirement("kubernetes.io/arch", selection.In, nm.ArchLabels); err == nil {
-				selector = selector.Add(*req)
-				added = true
+				requirements = append(requirements, *req)
 			}
 		}
 		if len(nm.OSLabels) > 1 {
 			if req, err := labels.NewRequirement("kubernetes.io/os", selection.In, nm.OSLabels); err == nil {
-				selector = selector.Add(*req)
-				added = true
+				requirements = append(requirements, *req)
+			}
+		}
+		for key, values := range map[string][]string{
+			matchinglogic.LabelLegacyArch: nm.LegacyArchLabels,
+			matchinglogic.LabelLegacyOS:   nm.LegacyOSLabels,
+			corev1.LabelTopologyZone:      nm.Zones,
+			corev1.LabelTopologyRegion:    nm.Regions,
+		} {
+			if len(values) == 0 {
+				continue
+			}
+			if req, err := labels.NewRequirement(key, selection.In, values); err == nil {
+				requirements = append(requirements, *req)
+			}
+		}
+		// A control-plane node carries either role label, which a selector cannot express; only exclusion is pushed
+		if nm.ControlPlane != nil && !*nm.ControlPlane {
+			for _, key := range []string{matchinglogic.LabelControlPlane, matchinglogic.LabelLegacyControlPlane} {
+				if req, err := labels.NewRequirement(key, selection.DoesNotExist, nil); err == nil {
+					requirements = append(requirements, *req)
+				}
 			}
 		}
-		if added {
-			*listOpts = append(*listOpts, client.MatchingLabelsSelector{Selector: selector})
+		if len(requirements) > 0 {
+			*listOpts = append(*listOpts, requirements)
 		}
 	}
 }
@@ -141,8 +282,112 @@ func FilterDeploymentList(listOpts *[]client.ListOption, match *autolabellerv1al
 		if cm.Namespace != "" {
 			*listOpts = append(*listOpts, client.InNamespace(cm.Namespace))
 		}
-		if len(cm.Labels) > 0 {
-			*listOpts = append(*listOpts, client.MatchingLabels(cm.Labels))
+		filterCommonLabels(listOpts, cm)
+	}
+}
+
+func FilterNamespaceList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	if match == nil {
+		return
+	}
+
+	// CommonMatch filters applied at API level (cluster-scoped, so no namespace)
+	// Name patterns are checked later in MatchesNamespaceDetailed (requires in-memory inspection)
+	// Annotations are checked later in MatchesNamespaceDetailed (requires in-memory inspection)
+	if cm := match.CommonMatch; cm != nil {
+		filterCommonLabels(listOpts, cm)
+	}
+
+	// NamespaceMatch.HasLabels → existence selectors applied at API level
+	// Phase, HasResourceQuota and Age are checked later in MatchesNamespaceDetailed (requires in-memory inspection)
+	if nm := match.NamespaceMatch; nm != nil && len(nm.HasLabels) > 0 {
+		*listOpts = append(*listOpts, client.HasLabels(nm.HasLabels))
+	}
+}
+
+func FilterServiceList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	if match == nil {
+		return
+	}
+
+	// CommonMatch filters applied at API level (reduces objects fetched)
+	// Name patterns are checked later in MatchesServiceDetailed (requires in-memory inspection)
+	// Annotations are checked later in MatchesServiceDetailed (requires in-memory inspection)
+	// ServiceMatch criteria inspect the spec and are all checked later in MatchesServiceDetailed
+	if cm := match.CommonMatch; cm != nil {
+		if cm.Namespace != "" {
+			*listOpts = append(*listOpts, client.InNamespace(cm.Namespace))
+		}
+		filterCommonLabels(listOpts, cm)
+	}
+}
+
+func FilterStatefulSetList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	filterNamespacedList(listOpts, match)
+}
+
+func FilterDaemonSetList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	filterNamespacedList(listOpts, match)
+}
+
+func FilterReplicaSetList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	filterNamespacedList(listOpts, match)
+}
+
+func FilterJobList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	filterNamespacedList(listOpts, match)
+}
+
+func FilterCronJobList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	filterNamespacedList(listOpts, match)
+}
+
+// FilterUnstructuredList pre-filters targets of kinds without typed support. clusterScoped comes from discovery,
+// as the namespace filter only applies to namespaced kinds.
+func FilterUnstructuredList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria, clusterScoped bool) {
+	if !clusterScoped {
+		filterNamespacedList(listOpts, match)
+		return
+	}
+	if match == nil || match.CommonMatch == nil {
+		return
+	}
+	filterCommonLabels(listOpts, match.CommonMatch)
+}
+
+// filterCommonLabels pushes the CommonMatch labels and the label requirements a label selector can express
+// (Exists, DoesNotExist, In and NotIn). Prefix, suffix, glob and regex requirements are checked later in memory.
+func filterCommonLabels(listOpts *[]client.ListOption, cm *autolabellerv1alpha1.CommonMatchCriteria) {
+	if len(cm.Labels) > 0 {
+		*listOpts = append(*listOpts, client.MatchingLabels(cm.Labels))
+	}
+	if requirements := matchinglogic.LabelSelectorRequirements(cm.LabelRequirements); len(requirements) > 0 {
+		*listOpts = append(*listOpts, labelRequirements(requirements))
+	}
+}
+
+// labelRequirements is a list option that adds requirements to the label selector of the other options.
+// client.MatchingLabelsSelector would replace the selector instead.
+type labelRequirements []labels.Requirement
+
+// ApplyToList implements client.ListOption.
+func (r labelRequirements) ApplyToList(opts *client.ListOptions) {
+	if opts.LabelSelector == nil {
+		opts.LabelSelector = labels.NewSelector()
+	}
+	opts.LabelSelector = opts.LabelSelector.Add(r...)
+}
+
+// filterNamespacedList applies the CommonMatch namespace and label pre-filters shared by namespaced workloads.
+// Name patterns, annotations, pod template and kind-specific criteria are checked later in memory.
+func filterNamespacedList(listOpts *[]client.ListOption, match *autolabellerv1alpha1.MatchCriteria) {
+	if match == nil {
+		return
+	}
+	if cm := match.CommonMatch; cm != nil {
+		if cm.Namespace != "" {
+			*listOpts = append(*listOpts, client.InNamespace(cm.Namespace))
 		}
+		filterCommonLabels(listOpts, cm)
 	}
 }
@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("ApplyLabelsToObject", func() {
+	var pod *corev1.Pod
+	const owner = "autolabeller-system/rule-a"
+	desired := map[string]string{"tier": "backend", "team": "platform"}
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "funnypod",
+			Namespace: "default",
+			Labels:    map[string]string{"tier": "frontend"},
+		}}
+	})
+
+	It("should overwrite conflicting values under Overwrite", func() {
+		changed, conflicts := ApplyLabelsToObject(pod, owner, desired, "Overwrite")
+		Expect(changed).To(BeTrue())
+		Expect(pod.Labels).To(Equal(desired))
+		Expect(GetLabelOwnership(pod)).To(Equal(LabelOwnership{owner: desired}))
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].Key).To(Equal("tier"))
+		Expect(conflicts[0].ExistingValue).To(Equal("frontend"))
+		Expect(conflicts[0].Resolution).To(Equal(ConflictResolutionOverwritten))
+	})
+
+	It("should only add missing keys under Merge", func() {
+		changed, conflicts := ApplyLabelsToObject(pod, owner, desired, "Merge")
+		Expect(changed).To(BeTrue())
+		Expect(pod.Labels).To(Equal(map[string]string{"tier": "frontend", "team": "platform"}))
+		Expect(GetLabelOwnership(pod)).To(Equal(LabelOwnership{owner: {"team": "platform"}}))
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].Resolution).To(Equal(ConflictResolutionKept))
+	})
+
+	It("should leave the object untouched under Ignore", func() {
+		changed, conflicts := ApplyLabelsToObject(pod, owner, desired, "Ignore")
+		Expect(changed).To(BeFalse())
+		Expect(pod.Labels).To(Equal(map[string]string{"tier": "frontend"}))
+		Expect(pod.Annotations).NotTo(HaveKey(OwnershipAnnotation))
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].Resolution).To(Equal(ConflictResolutionSkipped))
+	})
+
+	It("should reject the object under Error", func() {
+		changed, conflicts := ApplyLabelsToObject(pod, owner, desired, "Error")
+		Expect(changed).To(BeFalse())
+		Expect(pod.Labels).To(Equal(map[string]string{"tier": "frontend"}))
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].Resolution).To(Equal(ConflictResolutionRejected))
+	})
+
+	It("should apply labels without conflicts under every policy", func() {
+		pod.Labels = nil
+		changed, conflicts := ApplyLabelsToObject(pod, owner, desired, "Error")
+		Expect(changed).To(BeTrue())
+		Expect(conflicts).To(BeEmpty())
+		Expect(pod.Labels).To(Equal(desired))
+	})
+
+	It("should update its own earlier values without reporting a conflict", func() {
+		_, _ = ApplyLabelsToObject(pod, owner, map[string]string{"team": "platform"}, "Error")
+		changed, conflicts := ApplyLabelsToObject(pod, owner, map[string]string{"team": "data"}, "Error")
+		Expect(changed).To(BeTrue())
+		Expect(conflicts).To(BeEmpty())
+		Expect(pod.Labels).To(HaveKeyWithValue("team", "data"))
+	})
+
+	It("should not claim values that were set by hand", func() {
+		changed, _ := ApplyLabelsToObject(pod, owner, map[string]string{"tier": "frontend"}, "Merge")
+		Expect(changed).To(BeFalse())
+		Expect(GetLabelOwnership(pod)).To(BeEmpty())
+	})
+
+	It("should share values applied by another rule", func() {
+		const other = "autolabeller-system/rule-b"
+		_, _ = ApplyLabelsToObject(pod, other, map[string]string{"team": "platform"}, "Merge")
+		_, conflicts := ApplyLabelsToObject(pod, owner, map[string]string{"team": "platform"}, "Merge")
+		Expect(conflicts).To(BeEmpty())
+		Expect(GetLabelOwnership(pod).Owners("team", "platform")).To(Equal([]string{owner, other}))
+	})
+
+	It("should withdraw owned labels the rule no longer wants", func() {
+		_, _ = ApplyLabelsToObject(pod, owner, map[string]string{"team": "platform", "cost-center": "42"}, "Merge")
+		changed, _ := ApplyLabelsToObject(pod, owner, map[string]string{"team": "platform"}, "Merge")
+		Expect(changed).To(BeTrue())
+		Expect(pod.Labels).NotTo(HaveKey("cost-center"))
+		Expect(pod.Labels).To(HaveKeyWithValue("tier", "frontend"))
+		Expect(GetLabelOwnership(pod)).To(Equal(LabelOwnership{owner: {"team": "platform"}}))
+	})
+
+	It("should remove only the labels owned solely by the rule", func() {
+		const other = "autolabeller-system/rule-b"
+		_, _ = ApplyLabelsToObject(pod, owner, map[string]string{"team": "platform", "cost-center": "42"}, "Merge")
+		_, _ = ApplyLabelsToObject(pod, other, map[string]string{"team": "platform"}, "Merge")
+		Expect(RemoveOwnedLabels(pod, owner)).To(BeTrue())
+		Expect(pod.Labels).To(Equal(map[string]string{"tier": "frontend", "team": "platform"}))
+		Expect(GetLabelOwnership(pod)).To(Equal(LabelOwnership{other: {"team": "platform"}}))
+	})
+
+	It("should keep labels changed by hand after they were applied", func() {
+		_, _ = ApplyLabelsToObject(pod, owner, map[string]string{"team": "platform"}, "Merge")
+		pod.Labels["team"] = "data"
+		Expect(RemoveOwnedLabels(pod, owner)).To(BeTrue())
+		Expect(pod.Labels).To(HaveKeyWithValue("team", "data"))
+		Expect(pod.Annotations).NotTo(HaveKey(OwnershipAnnotation))
+	})
+})
+
+var _ = Describe("PrefilterCriteria", func() {
+	common := func(namespace string, labels map[string]string) *autolabellerv1alpha1.CommonMatchCriteria {
+		return &autolabellerv1alpha1.CommonMatchCriteria{Namespace: namespace, Labels: labels}
+	}
+
+	It("should keep criteria without groups as they are", func() {
+		match := &autolabellerv1alpha1.MatchCriteria{CommonMatch: common("prod", nil)}
+		Expect(PrefilterCriteria(match)).To(BeIdenticalTo(match))
+		Expect(PrefilterCriteria(nil)).To(BeNil())
+	})
+
+	It("should not narrow by anyOf or not branches", func() {
+		match := &autolabellerv1alpha1.MatchCriteria{
+			CommonMatch: common("", map[string]string{"app": "web"}),
+			AnyOf: []autolabellerv1alpha1.MatchCriteria{
+				{CommonMatch: common("prod", nil)},
+				{CommonMatch: common("", map[string]string{"tier": "core"})},
+			},
+			Not: &autolabellerv1alpha1.MatchCriteria{CommonMatch: common("kube-system", nil)},
+		}
+		prefilter := PrefilterCriteria(match)
+		Expect(prefilter.CommonMatch.Namespace).To(BeEmpty())
+		Expect(prefilter.CommonMatch.Labels).To(Equal(map[string]string{"app": "web"}))
+		Expect(prefilter.AnyOf).To(BeEmpty())
+		Expect(prefilter.Not).To(BeNil())
+	})
+
+	It("should narrow by allOf branches without changing the rule", func() {
+		match := &autolabellerv1alpha1.MatchCriteria{
+			CommonMatch: common("", map[string]string{"app": "web"}),
+			AllOf: []autolabellerv1alpha1.MatchCriteria{
+				{CommonMatch: common("prod", nil)},
+				{AllOf: []autolabellerv1alpha1.MatchCriteria{{CommonMatch: common("", map[string]string{"tier": "core"})}}},
+			},
+		}
+		prefilter := PrefilterCriteria(match)
+		Expect(prefilter.CommonMatch.Namespace).To(Equal("prod"))
+		Expect(prefilter.CommonMatch.Labels).To(Equal(map[string]string{"app": "web", "tier": "core"}))
+		Expect(match.CommonMatch.Namespace).To(BeEmpty())
+		Expect(match.CommonMatch.Labels).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("FilterNodeList", func() {
+	selectorFor := func(match *autolabellerv1alpha1.MatchCriteria) labels.Selector {
+		var opts []client.ListOption
+		FilterNodeList(&opts, match)
+		listOpts := &client.ListOptions{}
+		listOpts.ApplyOptions(opts)
+		return listOpts.LabelSelector
+	}
+
+	It("should combine labels, label requirements and multi-value arch selectors", func() {
+		selector := selectorFor(&autolabellerv1alpha1.MatchCriteria{
+			CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{
+				Labels: map[string]string{"pool": "general"},
+				LabelRequirements: []autolabellerv1alpha1.MetadataRequirement{
+					{Key: "node-role.kubernetes.io/control-plane", Operator: "DoesNotExist"},
+					{Key: "zone", Operator: "In", Values: []string{"a", "b"}},
+					{Key: "instance-type", Operator: "Prefix", Values: []string{"m5."}},
+				},
+			},
+			NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{ArchLabels: []string{"amd64", "arm64"}},
+		})
+		node := labels.Set{"pool": "general", "zone": "a", "kubernetes.io/arch": "arm64", "instance-type": "c6."}
+		Expect(selector.Matches(node)).To(BeTrue())
+
+		for key, value := range map[string]string{"pool": "gpu", "zone": "c", "kubernetes.io/arch": "s390x",
+			"node-role.kubernetes.io/control-plane": ""} {
+			other := labels.Merge(node, labels.Set{key: value})
+			Expect(selector.Matches(other)).To(BeFalse(), key)
+		}
+	})
+
+	It("should push legacy, topology and control-plane exclusion label criteria", func() {
+		selector := selectorFor(&autolabellerv1alpha1.MatchCriteria{
+			NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{
+				LegacyArchLabels: []string{"amd64"},
+				Zones:            []string{"eu-west-1a", "eu-west-1b"},
+				ControlPlane:     ptr.To(false),
+			},
+		})
+		node := labels.Set{"beta.kubernetes.io/arch": "amd64", "topology.kubernetes.io/zone": "eu-west-1b"}
+		Expect(selector.Matches(node)).To(BeTrue())
+		Expect(selector.Matches(labels.Merge(node, labels.Set{"node-role.kubernetes.io/master": ""}))).To(BeFalse())
+		Expect(selector.Matches(labels.Merge(node, labels.Set{"topology.kubernetes.io/zone": "us-east-1a"}))).To(BeFalse())
+	})
+
+	It("should not push control-plane selection, which needs either role label", func() {
+		var opts []client.ListOption
+		FilterNodeList(&opts, &autolabellerv1alpha1.MatchCriteria{
+			NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{ControlPlane: ptr.To(true)},
+		})
+		Expect(opts).To(BeEmpty())
+	})
+})
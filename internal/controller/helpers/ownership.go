@@ -0,0 +1,137 @@
+package helpers
+
+import (
+	"encoding/json"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// OwnershipAnnotation records which ClassificationRule applied which labels to a target.
+// The value is a JSON object keyed by rule ("namespace/name"), each holding the label keys and the
+// values the rule wrote, e.g. {"autolabeller-system/gpu-nodes":{"accelerator":"gpu"}}.
+const OwnershipAnnotation = "autolabeller.github.com/owned-labels"
+
+// LabelOwnership maps an owning rule key to the labels (key → value) that rule applied.
+type LabelOwnership map[string]map[string]string
+
+// RuleOwnerKey returns the key identifying rule in the ownership annotation.
+func RuleOwnerKey(rule *autolabellerv1alpha1.ClassificationRule) string {
+	return client.ObjectKeyFromObject(rule).String()
+}
+
+// GetLabelOwnership reads the ownership record from obj. A missing or malformed annotation yields an empty record,
+// which means the operator treats every existing label as foreign.
+func GetLabelOwnership(obj client.Object) LabelOwnership {
+	own := LabelOwnership{}
+	raw, ok := obj.GetAnnotations()[OwnershipAnnotation]
+	if !ok || raw == "" {
+		return own
+	}
+	if err := json.Unmarshal([]byte(raw), &own); err != nil {
+		return LabelOwnership{}
+	}
+	return own
+}
+
+// SetLabelOwnership writes the ownership record to obj, removing the annotation when no rule owns anything.
+// It returns whether the annotation changed.
+func SetLabelOwnership(obj client.Object, own LabelOwnership) bool {
+	for owner, keys := range own {
+		if len(keys) == 0 {
+			delete(own, owner)
+		}
+	}
+	annotations := obj.GetAnnotations()
+	current, had := annotations[OwnershipAnnotation]
+	if len(own) == 0 {
+		if !had {
+			return false
+		}
+		delete(annotations, OwnershipAnnotation)
+		obj.SetAnnotations(annotations)
+		return true
+	}
+	raw, err := json.Marshal(own)
+	if err != nil || (had && current == string(raw)) {
+		return false
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[OwnershipAnnotation] = string(raw)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// Owners returns the rules that own key with the given value, sorted.
+func (o LabelOwnership) Owners(key, value string) []string {
+	var owners []string
+	for owner, keys := range o {
+		if v, ok := keys[key]; ok && v == value {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// OwnedBy reports whether owner recorded key with value.
+func (o LabelOwnership) OwnedBy(owner, key, value string) bool {
+	v, ok := o[owner][key]
+	return ok && v == value
+}
+
+func (o LabelOwnership) claim(owner, key, value string) {
+	if o[owner] == nil {
+		o[owner] = map[string]string{}
+	}
+	o[owner][key] = value
+}
+
+func (o LabelOwnership) release(owner, key string) {
+	delete(o[owner], key)
+}
+
+// releaseLabel drops owner's claim on key and deletes the label from m when nobody else still relies on it.
+// The label is only deleted if it still carries the value owner wrote, so labels edited by hand are preserved.
+// It returns whether m was modified.
+func releaseLabel(m map[string]string, own LabelOwnership, owner, key string) bool {
+	recorded, ok := own[owner][key]
+	if !ok {
+		return false
+	}
+	own.release(owner, key)
+	current, present := m[key]
+	if !present || current != recorded || len(own.Owners(key, current)) > 0 {
+		return false
+	}
+	delete(m, key)
+	return true
+}
+
+// RemoveOwnedLabels withdraws every label owner applied to obj and drops owner from the ownership record.
+// Labels shared with another rule or changed by hand since they were applied are left in place.
+// It returns whether obj was modified.
+func RemoveOwnedLabels(obj client.Object, owner string) bool {
+	own := GetLabelOwnership(obj)
+	if len(own[owner]) == 0 {
+		return false
+	}
+	m := obj.GetLabels()
+	changed := false
+	for k := range own[owner] {
+		if releaseLabel(m, own, owner, k) {
+			changed = true
+		}
+	}
+	if changed {
+		obj.SetLabels(m)
+	}
+	if SetLabelOwnership(obj, own) {
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,96 @@
+package matchinglogic
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Batch workload matching", func() {
+	DescribeTable("ScheduleInterval",
+		func(schedule string, expected time.Duration) {
+			d, err := ScheduleInterval(schedule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(Equal(expected))
+		},
+		Entry("every five minutes", "*/5 * * * *", 5*time.Minute),
+		Entry("hourly descriptor", "@hourly", time.Hour),
+		Entry("twice an hour", "0,30 * * * *", 30*time.Minute),
+		Entry("uneven hours", "0 1,2,12 * * *", time.Hour),
+		Entry("daily with time zone", "CRON_TZ=Europe/Berlin 0 3 * * *", 24*time.Hour),
+		Entry("weekly", "0 0 * * 0", 7*24*time.Hour),
+	)
+
+	It("Should reject invalid schedules", func() {
+		_, err := ScheduleInterval("every hour")
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("Jobs", func() {
+		var job *batchv1.Job
+
+		BeforeEach(func() {
+			job = &batchv1.Job{Spec: batchv1.JobSpec{
+				Completions:             ptr.To[int32](10),
+				Parallelism:             ptr.To[int32](5),
+				TTLSecondsAfterFinished: ptr.To[int32](3600),
+			}}
+		})
+
+		match := func(jm *autolabellerv1alpha1.JobMatchCriteria) bool {
+			ok, _ := MatchesJobDetailed(&autolabellerv1alpha1.MatchCriteria{JobMatch: jm}, job, nil)
+			return ok
+		}
+
+		It("Should compare integer fields with their defaults", func() {
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{BackoffLimit: "==6", Completions: ">=10", Parallelism: "2..5"})).To(BeTrue())
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{TTLSecondsAfterFinished: "<=3600"})).To(BeTrue())
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{ActiveDeadlineSeconds: ">0"})).To(BeFalse())
+			job.Spec.ActiveDeadlineSeconds = ptr.To[int64](600)
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{ActiveDeadlineSeconds: ">0"})).To(BeTrue())
+		})
+
+		It("Should match suspend state and outcome", func() {
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{Suspend: ptr.To(false), Status: JobStatusActive})).To(BeTrue())
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{Status: JobStatusFailed})).To(BeTrue())
+			Expect(match(&autolabellerv1alpha1.JobMatchCriteria{Status: JobStatusComplete})).To(BeFalse())
+		})
+	})
+
+	It("Should match CronJobs by schedule, policy and job template", func() {
+		cj := &batchv1.CronJob{Spec: batchv1.CronJobSpec{
+			Schedule:          "*/15  * * * *",
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To[int32](0),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Image: "ghcr.io/acme/report:2"}},
+				}},
+			}},
+		}}
+		mc := &autolabellerv1alpha1.MatchCriteria{
+			CronJobMatch: &autolabellerv1alpha1.CronJobMatchCriteria{
+				Schedule:          "*/15 * * * *",
+				ScheduleInterval:  "<1h",
+				ConcurrencyPolicy: "Forbid",
+				Suspend:           ptr.To(false),
+			},
+			JobMatch: &autolabellerv1alpha1.JobMatchCriteria{BackoffLimit: "==0"},
+			PodMatch: &autolabellerv1alpha1.PodMatchCriteria{Images: []string{"*/report"}},
+		}
+		ok, fields := MatchesCronJobDetailed(mc, cj, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("cronJobMatch.scheduleInterval:15m0s"))
+
+		mc.CronJobMatch.ScheduleInterval = ">=24h"
+		ok, _ = MatchesCronJobDetailed(mc, cj, nil)
+		Expect(ok).To(BeFalse())
+	})
+})
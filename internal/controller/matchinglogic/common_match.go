@@ -0,0 +1,95 @@
+// Package matchinglogic evaluates ClassificationRule match criteria against target objects.
+//
+// When the controller lists targets, the helpers.Filter*List functions push the criteria a label or field selector
+// can express (namespace, labels and, for nodes, the NodeMatch label criteria) into the list call. The
+// Matches*Detailed functions still evaluate every criterion, so a single object delivered by a watch event or the
+// pod admission webhook can be evaluated on its own.
+package matchinglogic
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// matchShared evaluates the criteria that apply to every kind: CommonMatch, FieldMatch and Expression.
+func matchShared(mc *autolabellerv1alpha1.MatchCriteria, obj client.Object, clusterScoped bool, env *Environment) (bool, []string) {
+	ok, matchedFields := matchCommon(mc.CommonMatch, obj, clusterScoped, env)
+	if !ok || (len(mc.FieldMatch) == 0 && mc.Expression == "") {
+		return ok, matchedFields
+	}
+	content, err := objectContent(obj)
+	if err != nil {
+		return false, matchedFields
+	}
+	if len(mc.FieldMatch) > 0 {
+		ok, fieldFields := matchFields(mc.FieldMatch, content)
+		matchedFields = append(matchedFields, fieldFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+	if mc.Expression != "" {
+		if !MatchExpression(mc.Expression, content) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "expression")
+	}
+	return true, matchedFields
+}
+
+// matchCommon evaluates CommonMatch criteria against any object. Namespace and NamespaceSelector are skipped for
+// cluster-scoped objects.
+func matchCommon(cm *autolabellerv1alpha1.CommonMatchCriteria, obj client.Object, clusterScoped bool, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if cm == nil {
+		return true, matchedFields
+	}
+	if cm.Namespace != "" && !clusterScoped {
+		if obj.GetNamespace() != cm.Namespace {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "commonMatch.namespace")
+	}
+	if cm.NamespaceSelector != nil && !clusterScoped {
+		if !env.namespaceSelected(cm.NamespaceSelector, obj.GetNamespace()) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "commonMatch.namespaceSelector")
+	}
+	labels := obj.GetLabels()
+	for k, v := range cm.Labels {
+		if val, ok := labels[k]; !ok || val != v {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.labels[%s]", k))
+	}
+	for _, req := range cm.LabelRequirements {
+		if !matchMetadataRequirement(req, labels) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.labelRequirements[%s]", req.Key))
+	}
+	if name := cm.Name; name != "" {
+		if !MatchGlob(name, obj.GetName()) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "commonMatch.name")
+	}
+	annotations := obj.GetAnnotations()
+	for k, v := range cm.Annotations {
+		if val, ok := annotations[k]; !ok || !matchAnnotationValue(v, val) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.annotations[%s]", k))
+	}
+	for _, req := range cm.AnnotationRequirements {
+		if !matchMetadataRequirement(req, annotations) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.annotationRequirements[%s]", req.Key))
+	}
+	return true, matchedFields
+}
@@ -0,0 +1,119 @@
+package matchinglogic
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// Comparison operators accepted in criteria expressions. OpRange denotes an inclusive "low..high" range.
+const (
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+	OpGreaterEqual = ">="
+	OpGreater      = ">"
+	OpRange        = ".."
+)
+
+// operators is ordered so two-character operators are tried before their one-character prefixes.
+var operators = []string{OpLessEqual, OpGreaterEqual, OpEqual, OpNotEqual, OpLess, OpGreater, "="}
+
+// Comparison is a parsed comparison expression such as ">1", "<= 512Mi", "==5" or the inclusive range "2..4".
+// Operands are kept as strings so each criterion can interpret them (quantities, integers, versions).
+type Comparison struct {
+	// Operator is one of the Op* constants. A bare value means OpEqual.
+	Operator string
+	// Value is the operand, or the lower bound of a range.
+	Value string
+	// Upper is the upper bound of a range, empty otherwise.
+	Upper string
+}
+
+// ParseComparison splits expr into its operator and operand(s). It only checks the shape of the expression;
+// callers validate the operands for their value type.
+func ParseComparison(expr string) (Comparison, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return Comparison{}, fmt.Errorf("empty comparison expression")
+	}
+
+	if low, high, ok := strings.Cut(expr, OpRange); ok {
+		low, high = strings.TrimSpace(low), strings.TrimSpace(high)
+		if low == "" || high == "" {
+			return Comparison{}, fmt.Errorf("range %q must have the form low..high", expr)
+		}
+		if hasOperator(low) || hasOperator(high) {
+			return Comparison{}, fmt.Errorf("range %q must not contain comparison operators", expr)
+		}
+		return Comparison{Operator: OpRange, Value: low, Upper: high}, nil
+	}
+
+	op := OpEqual
+	for _, candidate := range operators {
+		if strings.HasPrefix(expr, candidate) {
+			op = candidate
+			expr = strings.TrimSpace(strings.TrimPrefix(expr, candidate))
+			break
+		}
+	}
+	if op == "=" {
+		op = OpEqual
+	}
+	if expr == "" {
+		return Comparison{}, fmt.Errorf("operator %s is missing an operand", op)
+	}
+	if hasOperator(expr) {
+		return Comparison{}, fmt.Errorf("operand %q must not contain comparison operators", expr)
+	}
+	return Comparison{Operator: op, Value: expr}, nil
+}
+
+func hasOperator(s string) bool {
+	return strings.ContainsAny(s, "<>=!")
+}
+
+// checkRange rejects a range whose lower bound exceeds its upper bound, since no value could satisfy it. compare
+// returns the sign of (low - high) for operands that have already been validated.
+func (c Comparison) checkRange(compare func(low, high string) int) error {
+	if c.Operator == OpRange && compare(c.Value, c.Upper) > 0 {
+		return fmt.Errorf("range %s..%s is empty: lower bound exceeds upper bound", c.Value, c.Upper)
+	}
+	return nil
+}
+
+// compareOrdered returns the sign of (a - b).
+func compareOrdered[T cmp.Ordered](a, b T) int {
+	return cmp.Compare(a, b)
+}
+
+// evaluate applies the comparison using compare, which returns the sign of (actual - operand) for an operand
+// string, or an error if the operand is not valid for the value type.
+func (c Comparison) evaluate(compare func(operand string) (int, error)) (bool, error) {
+	lower, err := compare(c.Value)
+	if err != nil {
+		return false, err
+	}
+	switch c.Operator {
+	case OpLess:
+		return lower < 0, nil
+	case OpLessEqual:
+		return lower <= 0, nil
+	case OpEqual:
+		return lower == 0, nil
+	case OpNotEqual:
+		return lower != 0, nil
+	case OpGreaterEqual:
+		return lower >= 0, nil
+	case OpGreater:
+		return lower > 0, nil
+	case OpRange:
+		upper, err := compare(c.Upper)
+		if err != nil {
+			return false, err
+		}
+		return lower >= 0 && upper <= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", c.Operator)
+}
@@ -0,0 +1,51 @@
+package matchinglogic
+
+import (
+	"regexp"
+	"sync"
+)
+
+// compileCache memoizes values compiled from source text, such as CEL programs and regular expressions from criteria
+// or the run intervals of CronJob schedules.
+// Criteria only change with the rule's spec, so each rule generation compiles them once, whichever code path
+// (reconciler, watch mapping or webhook) evaluates them first. The cache is emptied when it reaches its limit.
+type compileCache[T any] struct {
+	mu      sync.Mutex
+	limit   int
+	compile func(source string) (T, error)
+	entries map[string]compiled[T]
+}
+
+type compiled[T any] struct {
+	value T
+	err   error
+}
+
+func newCompileCache[T any](limit int, compile func(source string) (T, error)) *compileCache[T] {
+	return &compileCache[T]{limit: limit, compile: compile, entries: map[string]compiled[T]{}}
+}
+
+// get returns the compiled form of source, compiling it on first use. Compile errors are cached as well.
+func (c *compileCache[T]) get(source string) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[source]; ok {
+		return e.value, e.err
+	}
+	value, err := c.compile(source)
+	if len(c.entries) >= c.limit {
+		clear(c.entries)
+	}
+	c.entries[source] = compiled[T]{value: value, err: err}
+	return value, err
+}
+
+// anchoredRegexps caches the regular expressions of regex criteria.
+var anchoredRegexps = newCompileCache(1024, func(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+})
+
+// compileAnchored compiles pattern so it has to match the whole value.
+func compileAnchored(pattern string) (*regexp.Regexp, error) {
+	return anchoredRegexps.get(pattern)
+}
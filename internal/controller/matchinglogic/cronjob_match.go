@@ -0,0 +1,87 @@
+package matchinglogic
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesCronJobDetailed returns whether the CronJob matches and a list of fields that matched.
+// JobMatch and PodMatch criteria are evaluated against the job and pod templates.
+func MatchesCronJobDetailed(mc *autolabellerv1alpha1.MatchCriteria, cj *batchv1.CronJob, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, cj, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if cm := mc.CronJobMatch; cm != nil {
+		if cm.Schedule != "" {
+			if normalizeSchedule(cj.Spec.Schedule) != normalizeSchedule(cm.Schedule) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "cronJobMatch.schedule")
+		}
+		if cm.ScheduleInterval != "" {
+			interval, err := ScheduleInterval(cj.Spec.Schedule)
+			if err != nil {
+				return false, matchedFields
+			}
+			if ok, err := MatchDuration(cm.ScheduleInterval, interval); err != nil || !ok {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("cronJobMatch.scheduleInterval:%s", interval))
+		}
+		if cm.ConcurrencyPolicy != "" {
+			// An unset policy defaults to Allow
+			policy := cj.Spec.ConcurrencyPolicy
+			if policy == "" {
+				policy = batchv1.AllowConcurrent
+			}
+			if string(policy) != cm.ConcurrencyPolicy {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "cronJobMatch.concurrencyPolicy")
+		}
+		if cm.Suspend != nil {
+			suspended := cj.Spec.Suspend != nil && *cj.Spec.Suspend
+			if suspended != *cm.Suspend {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "cronJobMatch.suspend")
+		}
+	}
+
+	if jm := mc.JobMatch; jm != nil {
+		ok, specFields := matchJobSpec(jm, &cj.Spec.JobTemplate.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &cj.Spec.JobTemplate.Spec.Template.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesCronJobDetailed(sub, cj, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
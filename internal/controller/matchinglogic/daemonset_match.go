@@ -0,0 +1,128 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesDaemonSetDetailed returns whether the DaemonSet matches and a list of fields that matched.
+// PodMatch criteria are evaluated against the pod template.
+func MatchesDaemonSetDetailed(mc *autolabellerv1alpha1.MatchCriteria, ds *appsv1.DaemonSet, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, ds, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if dm := mc.DaemonSetMatch; dm != nil {
+		if dm.UpdateStrategy != "" {
+			// An unset strategy defaults to RollingUpdate
+			strategy := ds.Spec.UpdateStrategy.Type
+			if strategy == "" {
+				strategy = appsv1.RollingUpdateDaemonSetStrategyType
+			}
+			if string(strategy) != dm.UpdateStrategy {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "daemonSetMatch.updateStrategy")
+		}
+		for k, v := range dm.NodeSelector {
+			if val, ok := ds.Spec.Template.Spec.NodeSelector[k]; !ok || val != v {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("daemonSetMatch.nodeSelector[%s]", k))
+		}
+		for _, want := range dm.Tolerations {
+			tc, err := ParseTolerationCriterion(want)
+			if err != nil || !tc.toleratedBy(ds.Spec.Template.Spec.Tolerations) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("daemonSetMatch.tolerations:%s", want))
+		}
+	}
+
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &ds.Spec.Template.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesDaemonSetDetailed(sub, ds, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
+
+// TolerationCriterion is a parsed key[=value][:effect] toleration criterion.
+type TolerationCriterion struct {
+	Key      string
+	Value    string
+	HasValue bool
+	Effect   corev1.TaintEffect
+}
+
+var taintEffects = []corev1.TaintEffect{corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute}
+
+// ParseTolerationCriterion parses a key[=value][:effect] toleration criterion.
+func ParseTolerationCriterion(s string) (TolerationCriterion, error) {
+	var tc TolerationCriterion
+	kv, effect, hasEffect := strings.Cut(s, ":")
+	tc.Key, tc.Value, tc.HasValue = strings.Cut(kv, "=")
+	if msgs := validation.IsQualifiedName(tc.Key); len(msgs) > 0 {
+		return tc, fmt.Errorf("invalid toleration key %q: %s", tc.Key, strings.Join(msgs, "; "))
+	}
+	if msgs := validation.IsValidLabelValue(tc.Value); len(msgs) > 0 {
+		return tc, fmt.Errorf("invalid toleration value %q: %s", tc.Value, strings.Join(msgs, "; "))
+	}
+	if hasEffect {
+		tc.Effect = corev1.TaintEffect(effect)
+		if !slices.Contains(taintEffects, tc.Effect) {
+			return tc, fmt.Errorf("invalid toleration effect %q, must be one of NoSchedule, PreferNoSchedule, NoExecute", effect)
+		}
+	}
+	return tc, nil
+}
+
+// toleratedBy reports whether any of tolerations tolerates the criterion's taint. Without a value any toleration
+// for the key counts; without an effect tolerating the taint under any effect is enough.
+func (tc TolerationCriterion) toleratedBy(tolerations []corev1.Toleration) bool {
+	effects := taintEffects
+	if tc.Effect != "" {
+		effects = []corev1.TaintEffect{tc.Effect}
+	}
+	for i := range tolerations {
+		t := &tolerations[i]
+		for _, effect := range effects {
+			if !tc.HasValue {
+				keyMatches := t.Key == tc.Key || (t.Key == "" && t.Operator == corev1.TolerationOpExists)
+				if keyMatches && (t.Effect == "" || t.Effect == effect) {
+					return true
+				}
+				continue
+			}
+			if t.ToleratesTaint(&corev1.Taint{Key: tc.Key, Value: tc.Value, Effect: effect}) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -9,41 +9,26 @@ import (
 )
 
 // MatchesDeploymentDetailed returns whether the Deployment matches and a list of fields that matched.
-// Note: Namespace and CommonMatch.Labels are pre-filtered by FilterDeploymentList, so we only check items
-// that require in-memory inspection (name, annotations, and Deployment-specific criteria).
-func MatchesDeploymentDetailed(mc *autolabellerv1alpha1.MatchCriteria, deployment *appsv1.Deployment) (bool, []string) {
+func MatchesDeploymentDetailed(mc *autolabellerv1alpha1.MatchCriteria, deployment *appsv1.Deployment, env *Environment) (bool, []string) {
 	matchedFields := []string{}
 	if mc == nil {
 		return true, matchedFields
 	}
 
-	if cm := mc.CommonMatch; cm != nil {
-		// Namespace and Labels are already pre-filtered by FilterDeploymentList, skip them here
-		if name := cm.Name; name != "" {
-			if deployment.Name != name {
-				return false, matchedFields
-			}
-			matchedFields = append(matchedFields, "commonMatch.name")
-		}
-		for k, v := range cm.Annotations {
-			if deployment.Annotations[k] != v {
-				return false, matchedFields
-			}
-			matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.annotations[%s]", k))
-		}
+	ok, commonFields := matchShared(mc, deployment, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
 	}
 
 	if pm := mc.DeploymentMatch; pm != nil {
-		// Replicas: exact match on desired replicas when provided
+		// Replicas: integer expression against the desired replicas (defaults to 1 when unset)
 		if pm.Replicas != "" {
-			desired := int32(1)
-			if deployment.Spec.Replicas != nil {
-				desired = *deployment.Spec.Replicas
-			}
-			if fmt.Sprintf("%d", desired) != pm.Replicas {
+			ok, desired := matchReplicas(pm.Replicas, deployment.Spec.Replicas)
+			if !ok {
 				return false, matchedFields
 			}
-			matchedFields = append(matchedFields, "deploymentMatch.replicas")
+			matchedFields = append(matchedFields, fmt.Sprintf("deploymentMatch.replicas:%d", desired))
 		}
 
 		// Strategy: RollingUpdate or Recreate
@@ -70,5 +55,22 @@ func MatchesDeploymentDetailed(mc *autolabellerv1alpha1.MatchCriteria, deploymen
 		}
 	}
 
+	// Pod criteria are evaluated against the pod template
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &deployment.Spec.Template.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesDeploymentDetailed(sub, deployment, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
 	return true, matchedFields
 }
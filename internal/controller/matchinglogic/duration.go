@@ -0,0 +1,78 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"time"
+)
+
+// MatchDuration reports whether actual satisfies the duration expression expr, e.g. ">=24h" or "1h..168h".
+func MatchDuration(expr string, actual time.Duration) (bool, error) {
+	cmp, err := parseDurationExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	return cmp.evaluate(func(operand string) (int, error) {
+		d, err := time.ParseDuration(operand)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case actual < d:
+			return -1, nil
+		case actual > d:
+			return 1, nil
+		}
+		return 0, nil
+	})
+}
+
+// parseDurationExpression parses expr and checks that every operand is a Go duration.
+func parseDurationExpression(expr string) (Comparison, error) {
+	cmp, err := ParseComparison(expr)
+	if err != nil {
+		return Comparison{}, err
+	}
+	for _, operand := range []string{cmp.Value, cmp.Upper} {
+		if operand == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(operand); err != nil {
+			return Comparison{}, fmt.Errorf("%q is not a valid duration (e.g. 30m, 24h)", operand)
+		}
+	}
+	if err := cmp.checkRange(func(low, high string) int {
+		l, _ := time.ParseDuration(low)
+		h, _ := time.ParseDuration(high)
+		return compareOrdered(l, h)
+	}); err != nil {
+		return Comparison{}, err
+	}
+	return cmp, nil
+}
+
+// durationRecheckAfter returns how long until elapsed crosses a threshold of the duration expression expr, so a
+// match against it may change without an object event. It returns 0 when every threshold has already passed.
+func durationRecheckAfter(expr string, elapsed time.Duration) time.Duration {
+	cmp, err := parseDurationExpression(expr)
+	if err != nil {
+		return 0
+	}
+	var next time.Duration
+	for _, operand := range []string{cmp.Value, cmp.Upper} {
+		threshold, err := time.ParseDuration(operand)
+		if err != nil || threshold < elapsed {
+			continue
+		}
+		// Strict comparisons only flip once the threshold has passed
+		next = Sooner(next, threshold-elapsed+time.Second)
+	}
+	return next
+}
+
+// Sooner returns the shorter of two durations, where 0 means none. It combines recheck and requeue intervals.
+func Sooner(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
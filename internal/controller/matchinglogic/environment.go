@@ -0,0 +1,12 @@
+package matchinglogic
+
+// Environment carries cluster state that some criteria need beyond the target object itself.
+// Fields are only populated when the rule being evaluated uses the criteria that read them.
+type Environment struct {
+	// QuotaNamespaces is the set of namespaces that contain at least one ResourceQuota.
+	QuotaNamespaces map[string]struct{}
+
+	// SelectedNamespaces holds the namespaces selected by each namespaceSelector in the criteria, keyed by
+	// metav1.FormatLabelSelector. Selectors are resolved once per pass instead of once per target.
+	SelectedNamespaces map[string]map[string]struct{}
+}
@@ -0,0 +1,63 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// ExpressionCostLimit bounds the runtime cost of a single evaluation of a match expression. Evaluations that
+// exceed it are aborted and do not match.
+const ExpressionCostLimit = 1_000_000
+
+// MaxExpressionLength is the longest match expression accepted, as enforced by the CRD schema for top-level criteria.
+const MaxExpressionLength = 4096
+
+// expressionEnv declares the variables available to match expressions: the target object as "object".
+var expressionEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		ext.Strings(),
+	)
+})
+
+// programs caches compiled match expressions by their source.
+var programs = newCompileCache(512, compileExpression)
+
+// CompileExpression compiles a CEL match expression, which must evaluate to a boolean. The returned error
+// carries the CEL diagnostics.
+func CompileExpression(expr string) (cel.Program, error) {
+	return programs.get(expr)
+}
+
+func compileExpression(expr string) (cel.Program, error) {
+	env, err := expressionEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if out := ast.OutputType(); !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must evaluate to bool, not %s", out)
+	}
+	return env.Program(ast, cel.CostLimit(ExpressionCostLimit))
+}
+
+// MatchExpression evaluates expr with content bound as "object". Expressions that fail to compile, fail at runtime
+// (e.g. by selecting a missing field) or exceed ExpressionCostLimit do not match.
+func MatchExpression(expr string, content map[string]any) bool {
+	program, err := CompileExpression(expr)
+	if err != nil {
+		return false
+	}
+	out, _, err := program.Eval(map[string]any{"object": content})
+	if err != nil {
+		return false
+	}
+	matched, ok := out.Value().(bool)
+	return ok && matched
+}
@@ -0,0 +1,70 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Expression matching", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "nginx:1.27"},
+					{Name: "agent", Image: "agent:2", SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)}},
+				},
+				Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			},
+		}
+	})
+
+	match := func(expr string) (bool, []string) {
+		return MatchesPodDetailed(&autolabellerv1alpha1.MatchCriteria{Expression: expr}, pod, nil)
+	}
+
+	It("Should bind the target as object", func() {
+		ok, fields := match("object.metadata.name == 'web'")
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("expression"))
+	})
+
+	It("Should evaluate macros over lists", func() {
+		ok, _ := match("object.spec.containers.exists(c, has(c.securityContext) && c.securityContext.privileged == true)")
+		Expect(ok).To(BeTrue())
+		ok, _ = match("size(object.spec.volumes.filter(v, has(v.hostPath))) > 0")
+		Expect(ok).To(BeFalse())
+		pod.Spec.Volumes[0].VolumeSource = corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}}
+		ok, _ = match("size(object.spec.volumes.filter(v, has(v.hostPath))) > 0")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Should not match when evaluation fails", func() {
+		ok, _ := match("object.spec.priorityClassName == 'high'")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should stop evaluations that exceed the cost limit", func() {
+		ok, _ := match("[1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(a, [1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(b, " +
+			"[1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(c, [1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(d, " +
+			"[1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(e, [1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(f, a + b + c + d + e + f > 0))))))")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should report compile errors and non-boolean results", func() {
+		for _, expr := range []string{"object.metadata.name ==", "object.metadata.name + 1 == 'x' ||", "'not a bool'"} {
+			errs := ValidateMatchCriteria("", "Pod", &autolabellerv1alpha1.MatchCriteria{Expression: expr}, field.NewPath("match"))
+			Expect(errs).To(HaveLen(1), expr)
+			Expect(errs[0].Field).To(Equal("match.expression"))
+		}
+		Expect(ValidateMatchCriteria("", "Pod", &autolabellerv1alpha1.MatchCriteria{Expression: "object.spec.hostNetwork == true"}, field.NewPath("match"))).To(BeEmpty())
+	})
+})
@@ -0,0 +1,291 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// Operators for FieldRequirement.Operator. An empty operator means FieldOpEquals.
+const (
+	FieldOpEquals       = "Equals"
+	FieldOpIn           = "In"
+	FieldOpExists       = "Exists"
+	FieldOpDoesNotExist = "DoesNotExist"
+	FieldOpRegex        = "Regex"
+	FieldOpNumeric      = "Numeric"
+	FieldOpQuantity     = "Quantity"
+)
+
+// Array semantics for FieldRequirement.Match. An empty value means FieldMatchAny.
+const (
+	FieldMatchAny = "Any"
+	FieldMatchAll = "All"
+)
+
+// matchFields evaluates FieldMatch requirements against the unstructured content of an object.
+func matchFields(reqs []autolabellerv1alpha1.FieldRequirement, content map[string]any) (bool, []string) {
+	matchedFields := []string{}
+	for _, req := range reqs {
+		if ok, err := MatchField(req, content); err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("fieldMatch[%s]", req.Path))
+	}
+	return true, matchedFields
+}
+
+// MatchField reports whether the unstructured content of an object satisfies req.
+func MatchField(req autolabellerv1alpha1.FieldRequirement, content map[string]any) (bool, error) {
+	values, found, err := selectField(req.Path, content)
+	if err != nil {
+		return false, err
+	}
+	switch req.Operator {
+	case FieldOpExists:
+		return found, nil
+	case FieldOpDoesNotExist:
+		return !found, nil
+	}
+	if len(values) == 0 {
+		return false, nil
+	}
+	test, err := fieldTest(req)
+	if err != nil {
+		return false, err
+	}
+	all := req.Match == FieldMatchAll
+	for _, v := range values {
+		if ok := test(v); ok != all {
+			return ok, nil
+		}
+	}
+	return all, nil
+}
+
+// ParseFieldPath compiles a FieldRequirement path. Paths may omit the surrounding braces and the leading dot,
+// so "spec.replicas", ".spec.replicas" and "{.spec.replicas}" are equivalent. Missing keys select nothing.
+func ParseFieldPath(path string) (*jsonpath.JSONPath, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "{") {
+		if !strings.HasPrefix(path, ".") {
+			path = "." + path
+		}
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("fieldMatch").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+// fieldPaths caches parsed field paths by their source.
+var fieldPaths = newCompileCache(1024, func(path string) (*fieldPath, error) {
+	jp, err := ParseFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldPath{jp: jp}, nil
+})
+
+// fieldPath is a parsed field path shared by every evaluation of it. A JSONPath keeps state while it is executed,
+// so executions are serialized.
+type fieldPath struct {
+	mu sync.Mutex
+	jp *jsonpath.JSONPath
+}
+
+func (p *fieldPath) find(content map[string]any) ([][]reflect.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jp.FindResults(content)
+}
+
+// selectField returns the values path selects in content and whether it selects anything at all, even an empty
+// list. Selected lists contribute their elements; null values count as missing.
+func selectField(path string, content map[string]any) ([]any, bool, error) {
+	fp, err := fieldPaths.get(path)
+	if err != nil {
+		return nil, false, err
+	}
+	results, err := fp.find(content)
+	if err != nil {
+		return nil, false, err
+	}
+	var values []any
+	found := false
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() || v.Interface() == nil {
+				continue
+			}
+			found = true
+			if list, ok := v.Interface().([]any); ok {
+				values = append(values, list...)
+				continue
+			}
+			values = append(values, v.Interface())
+		}
+	}
+	return values, found, nil
+}
+
+// fieldTest returns the predicate req's operator applies to each selected value.
+func fieldTest(req autolabellerv1alpha1.FieldRequirement) (func(value any) bool, error) {
+	switch req.Operator {
+	case FieldOpEquals, "":
+		return func(value any) bool {
+			s, ok := scalarString(value)
+			return ok && s == req.Value
+		}, nil
+	case FieldOpIn:
+		return func(value any) bool {
+			s, ok := scalarString(value)
+			return ok && slices.Contains(req.Values, s)
+		}, nil
+	case FieldOpRegex:
+		re, err := compileAnchored(req.Value)
+		if err != nil {
+			return nil, err
+		}
+		return func(value any) bool {
+			s, ok := scalarString(value)
+			return ok && re.MatchString(s)
+		}, nil
+	case FieldOpNumeric:
+		cmp, err := parseNumericExpression(req.Value)
+		if err != nil {
+			return nil, err
+		}
+		return func(value any) bool {
+			n, ok := numericValue(value)
+			if !ok {
+				return false
+			}
+			matched, err := cmp.evaluate(func(operand string) (int, error) {
+				o, err := strconv.ParseFloat(operand, 64)
+				return compareFloat(n, o), err
+			})
+			return err == nil && matched
+		}, nil
+	case FieldOpQuantity:
+		return func(value any) bool {
+			q, ok := quantityValue(value)
+			if !ok {
+				return false
+			}
+			matched, err := MatchQuantity(req.Value, q)
+			return err == nil && matched
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown fieldMatch operator %q", req.Operator)
+}
+
+// parseNumericExpression parses a comparison expression whose operands are decimal numbers.
+func parseNumericExpression(expr string) (Comparison, error) {
+	cmp, err := ParseComparison(expr)
+	if err != nil {
+		return Comparison{}, err
+	}
+	for _, operand := range []string{cmp.Value, cmp.Upper} {
+		if operand == "" {
+			continue
+		}
+		if f, err := strconv.ParseFloat(operand, 64); err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return Comparison{}, fmt.Errorf("%q is not a valid number", operand)
+		}
+	}
+	if err := cmp.checkRange(func(low, high string) int {
+		l, _ := strconv.ParseFloat(low, 64)
+		h, _ := strconv.ParseFloat(high, 64)
+		return compareFloat(l, h)
+	}); err != nil {
+		return Comparison{}, err
+	}
+	return cmp, nil
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// objectContent returns the unstructured form of obj that FieldMatch and Expression are evaluated against,
+// so the same paths work for every kind. Typed objects read from the API carry no apiVersion and kind, so they
+// are filled in from the client-go scheme.
+func objectContent(obj client.Object) (map[string]any, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		if gvk, err = apiutil.GVKForObject(obj, scheme.Scheme); err != nil {
+			return content, nil
+		}
+	}
+	content["apiVersion"], content["kind"] = gvk.GroupVersion().String(), gvk.Kind
+	return content, nil
+}
+
+// scalarString returns the string form of a string, number or boolean field value.
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// numericValue returns a number field value, or a string field holding a number, as a float.
+func numericValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// quantityValue returns a field value holding a resource quantity, e.g. "500m" or 2.
+func quantityValue(value any) (resource.Quantity, bool) {
+	s, ok := scalarString(value)
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(s)
+	return q, err == nil
+}
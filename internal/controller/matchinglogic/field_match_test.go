@@ -0,0 +1,73 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Field requirements", func() {
+	content := map[string]any{
+		"spec": map[string]any{
+			"priorityClassName": "high",
+			"replicas":          int64(3),
+			"paused":            false,
+			"tolerations":       []any{},
+			"containers": []any{
+				map[string]any{"name": "app", "image": "registry.example.com/app:1.2", "resources": map[string]any{
+					"requests": map[string]any{"memory": "256Mi"},
+				}},
+				map[string]any{"name": "sidecar", "image": "envoyproxy/envoy:v1.30", "resources": map[string]any{
+					"requests": map[string]any{"memory": "1Gi"},
+				}},
+			},
+		},
+		"metadata": map[string]any{"ownerReferences": nil},
+	}
+
+	DescribeTable("evaluating requirements",
+		func(req autolabellerv1alpha1.FieldRequirement, expected bool) {
+			Expect(validateFieldRequirement(req, field.NewPath("fieldMatch"))).To(BeEmpty())
+			ok, err := MatchField(req, content)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("equals without operator", autolabellerv1alpha1.FieldRequirement{Path: "spec.priorityClassName", Value: "high"}, true),
+		Entry("equals on a number", autolabellerv1alpha1.FieldRequirement{Path: "{.spec.replicas}", Operator: FieldOpEquals, Value: "3"}, true),
+		Entry("equals on a boolean", autolabellerv1alpha1.FieldRequirement{Path: ".spec.paused", Operator: FieldOpEquals, Value: "true"}, false),
+		Entry("in", autolabellerv1alpha1.FieldRequirement{Path: "spec.priorityClassName", Operator: FieldOpIn, Values: []string{"low", "high"}}, true),
+		Entry("exists", autolabellerv1alpha1.FieldRequirement{Path: "spec.priorityClassName", Operator: FieldOpExists}, true),
+		Entry("exists on an empty list", autolabellerv1alpha1.FieldRequirement{Path: "spec.tolerations", Operator: FieldOpExists}, true),
+		Entry("exists on a null value", autolabellerv1alpha1.FieldRequirement{Path: "metadata.ownerReferences", Operator: FieldOpExists}, false),
+		Entry("does not exist", autolabellerv1alpha1.FieldRequirement{Path: "spec.nodeName", Operator: FieldOpDoesNotExist}, true),
+		Entry("missing fields never match", autolabellerv1alpha1.FieldRequirement{Path: "spec.nodeName", Operator: FieldOpEquals}, false),
+		Entry("regex is anchored", autolabellerv1alpha1.FieldRequirement{Path: "spec.priorityClassName", Operator: FieldOpRegex, Value: "hi"}, false),
+		Entry("regex", autolabellerv1alpha1.FieldRequirement{Path: "spec.priorityClassName", Operator: FieldOpRegex, Value: "hi.*"}, true),
+		Entry("numeric compare", autolabellerv1alpha1.FieldRequirement{Path: "spec.replicas", Operator: FieldOpNumeric, Value: ">=2.5"}, true),
+		Entry("numeric range", autolabellerv1alpha1.FieldRequirement{Path: "spec.replicas", Operator: FieldOpNumeric, Value: "4..10"}, false),
+		Entry("numeric on a string", autolabellerv1alpha1.FieldRequirement{Path: "spec.priorityClassName", Operator: FieldOpNumeric, Value: ">0"}, false),
+		Entry("any element", autolabellerv1alpha1.FieldRequirement{Path: "spec.containers[*].image", Operator: FieldOpRegex, Value: "envoyproxy/.*"}, true),
+		Entry("all elements", autolabellerv1alpha1.FieldRequirement{Path: "spec.containers[*].image", Operator: FieldOpRegex, Value: "envoyproxy/.*", Match: FieldMatchAll}, false),
+		Entry("quantity on any element", autolabellerv1alpha1.FieldRequirement{Path: "spec.containers[*].resources.requests.memory", Operator: FieldOpQuantity, Value: ">512Mi"}, true),
+		Entry("quantity on all elements", autolabellerv1alpha1.FieldRequirement{Path: "spec.containers[*].resources.requests.memory", Operator: FieldOpQuantity, Value: ">512Mi", Match: FieldMatchAll}, false),
+		Entry("all on an empty selection", autolabellerv1alpha1.FieldRequirement{Path: "spec.tolerations", Operator: FieldOpEquals, Match: FieldMatchAll}, false),
+	)
+
+	It("Should reject malformed requirements", func() {
+		for _, req := range []autolabellerv1alpha1.FieldRequirement{
+			{Path: "spec.containers[", Value: "x"},
+			{Path: "spec.replicas", Operator: FieldOpNumeric, Value: ">three"},
+			{Path: "spec.replicas", Operator: FieldOpNumeric, Value: "10..4"},
+			{Path: "spec.replicas", Operator: FieldOpQuantity},
+			{Path: "spec.containers[*].resources.requests.memory", Operator: FieldOpQuantity, Value: "2Gi..1Gi"},
+			{Path: "spec.priorityClassName", Operator: FieldOpRegex, Value: "("},
+			{Path: "spec.priorityClassName", Operator: FieldOpIn},
+			{Path: "spec.priorityClassName", Operator: FieldOpExists, Value: "high"},
+			{Path: "spec.priorityClassName", Values: []string{"high"}},
+		} {
+			Expect(validateFieldRequirement(req, field.NewPath("fieldMatch"))).NotTo(BeEmpty(), "%+v", req)
+		}
+	})
+})
@@ -0,0 +1,23 @@
+package matchinglogic
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesUnstructuredDetailed returns whether an object of an arbitrary kind matches and a list of fields that
+// matched. Only the criteria shared by every kind apply. Objects without a namespace are treated as cluster-scoped.
+func MatchesUnstructuredDetailed(mc *autolabellerv1alpha1.MatchCriteria, obj *unstructured.Unstructured, env *Environment) (bool, []string) {
+	if mc == nil {
+		return true, []string{}
+	}
+	ok, matchedFields := matchShared(mc, obj, obj.GetNamespace() == "", env)
+	if !ok {
+		return false, matchedFields
+	}
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesUnstructuredDetailed(sub, obj, env)
+	})
+	return ok, append(matchedFields, groupFields...)
+}
@@ -0,0 +1,128 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Generic target matching", func() {
+	var cert *unstructured.Unstructured
+
+	BeforeEach(func() {
+		cert = &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata": map[string]any{
+				"name":      "api-tls",
+				"namespace": "payments",
+				"labels":    map[string]any{"team": "payments"},
+			},
+			"spec": map[string]any{
+				"issuerRef":     map[string]any{"name": "letsencrypt", "kind": "ClusterIssuer"},
+				"duration":      "2160h",
+				"isCA":          false,
+				"revisionLimit": int64(3),
+				"dnsNames":      []any{"api.example.com"},
+			},
+		}}
+	})
+
+	match := func(mc *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesUnstructuredDetailed(mc, cert, nil)
+	}
+
+	It("Should match common metadata", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{
+			Namespace: "payments",
+			Name:      "api-*",
+			Labels:    map[string]string{"team": "payments"},
+		}}
+		ok, fields := match(mc)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElements("commonMatch.namespace", "commonMatch.name", "commonMatch.labels[team]"))
+		cert.SetNamespace("billing")
+		ok, _ = match(mc)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should ignore the namespace criterion for cluster-scoped objects", func() {
+		cert.SetNamespace("")
+		mc := &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Namespace: "payments"}}
+		ok, _ := match(mc)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Should match field paths by the string form of scalar values", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{FieldMatch: []autolabellerv1alpha1.FieldRequirement{
+			{Path: "spec.issuerRef.name", Value: "letsencrypt"},
+			{Path: ".spec.isCA", Value: "false"},
+			{Path: "spec.revisionLimit", Value: "3"},
+		}}
+		ok, fields := match(mc)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("fieldMatch[spec.issuerRef.name]"))
+
+		mc.FieldMatch[2].Value = "4"
+		ok, _ = match(mc)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should not match missing or non-scalar fields", func() {
+		for _, path := range []string{"spec.secretName", "spec.dnsNames", "spec.issuerRef"} {
+			mc := &autolabellerv1alpha1.MatchCriteria{FieldMatch: []autolabellerv1alpha1.FieldRequirement{{Path: path, Value: ""}}}
+			ok, _ := match(mc)
+			Expect(ok).To(BeFalse(), path)
+		}
+	})
+
+	It("Should evaluate field paths against typed objects", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       corev1.PodSpec{ServiceAccountName: "web", NodeName: "node-a"},
+		}
+		mc := &autolabellerv1alpha1.MatchCriteria{FieldMatch: []autolabellerv1alpha1.FieldRequirement{{Path: "spec.nodeName", Value: "node-a"}}}
+		ok, _ := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		pod.Spec.NodeName = "node-b"
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should expose the apiVersion and kind of typed objects", func() {
+		deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+		mc := &autolabellerv1alpha1.MatchCriteria{
+			FieldMatch: []autolabellerv1alpha1.FieldRequirement{{Path: "kind", Value: "Deployment"}},
+			Expression: "object.apiVersion == 'apps/v1'",
+		}
+		ok, _ := MatchesDeploymentDetailed(mc, deploy, nil)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Should evaluate a shared field path from concurrent reconciles", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{FieldMatch: []autolabellerv1alpha1.FieldRequirement{{Path: "spec.nodeName", Value: "node-a"}}}
+		var wg sync.WaitGroup
+		results := make([]bool, 64)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: fmt.Sprintf("node-%c", 'a'+i%2)}}
+				results[i], _ = MatchesPodDetailed(mc, pod, nil)
+			}()
+		}
+		wg.Wait()
+		for i, ok := range results {
+			Expect(ok).To(Equal(i%2 == 0), "pod %d", i)
+		}
+	})
+})
@@ -0,0 +1,134 @@
+package matchinglogic
+
+import "strings"
+
+// MatchGlob reports whether s matches pattern, where '*' matches any run of characters (including none)
+// and '?' matches exactly one character. Unlike path.Match, '*' also spans '/' so patterns such as
+// "*/ml-*" can be written against image references. A pattern without wildcards is an exact match.
+func MatchGlob(pattern, s string) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		return pattern == s
+	}
+	p, t := []rune(pattern), []rune(s)
+	pi, ti := 0, 0
+	star, mark := -1, 0
+	for ti < len(t) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == t[ti]):
+			pi++
+			ti++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, ti
+			pi++
+		case star >= 0:
+			// backtrack: let the last '*' absorb one more character
+			pi = star + 1
+			mark++
+			ti = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+const (
+	defaultRegistry   = "docker.io"
+	officialRepoName  = "library"
+	defaultImageTag   = "latest"
+	legacyDockerIndex = "index.docker.io"
+)
+
+// imageReference is a container image reference split into its parts with Docker's defaults applied:
+// "nginx" becomes registry "docker.io", repository "library/nginx" and tag "latest".
+type imageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseImageReference splits ref into registry, repository, tag and digest. The first path component is treated
+// as a registry when it looks like a host (contains '.' or ':', or is "localhost"), as the container runtime does.
+func parseImageReference(ref string) imageReference {
+	var ir imageReference
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ir.Digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ir.Tag = name[:i], name[i+1:]
+	}
+	if i := strings.Index(name, "/"); i >= 0 && (strings.ContainsAny(name[:i], ".:") || name[:i] == "localhost") {
+		ir.Registry, ir.Repository = name[:i], name[i+1:]
+	} else {
+		ir.Registry, ir.Repository = defaultRegistry, name
+	}
+	if ir.Registry == legacyDockerIndex {
+		ir.Registry = defaultRegistry
+	}
+	if ir.Registry == defaultRegistry && !strings.Contains(ir.Repository, "/") {
+		ir.Repository = officialRepoName + "/" + ir.Repository
+	}
+	if ir.Tag == "" && ir.Digest == "" {
+		ir.Tag = defaultImageTag
+	}
+	return ir
+}
+
+// names returns the spellings a user may use for the image without tag or digest, from fully qualified
+// ("docker.io/library/nginx") to familiar ("nginx").
+func (ir imageReference) names() []string {
+	names := []string{ir.Registry + "/" + ir.Repository, ir.Repository}
+	if ir.Registry == defaultRegistry {
+		if short, ok := strings.CutPrefix(ir.Repository, officialRepoName+"/"); ok {
+			names = append(names, short, defaultRegistry+"/"+short)
+		}
+	}
+	return names
+}
+
+// hasImageVersion reports whether an image pattern pins a tag or digest. A ':' only denotes a tag when it
+// follows the last '/', so registry ports ("localhost:5000/app") are not mistaken for tags.
+func hasImageVersion(pattern string) bool {
+	return strings.Contains(pattern, "@") || strings.LastIndex(pattern, ":") > strings.LastIndex(pattern, "/")
+}
+
+// MatchImage reports whether the container image matches pattern. Patterns are globs (see MatchGlob) and are
+// compared against the fully qualified and familiar forms of the image, so "nginx", "library/nginx" and
+// "docker.io/library/nginx" all match "docker.io/library/nginx:1.27". A pattern without a tag or digest matches
+// any tag; one with a tag ("nginx:1.*") or digest must match it too. Untagged images are treated as ":latest".
+func MatchImage(pattern, image string) bool {
+	if MatchGlob(pattern, image) {
+		return true
+	}
+	ir := parseImageReference(image)
+	withVersion := hasImageVersion(pattern)
+	for _, name := range ir.names() {
+		if !withVersion {
+			if MatchGlob(pattern, name) {
+				return true
+			}
+			continue
+		}
+		candidates := []string{}
+		if ir.Tag != "" {
+			candidates = append(candidates, name+":"+ir.Tag)
+		}
+		if ir.Digest != "" {
+			candidates = append(candidates, name+"@"+ir.Digest)
+			if ir.Tag != "" {
+				candidates = append(candidates, name+":"+ir.Tag+"@"+ir.Digest)
+			}
+		}
+		for _, c := range candidates {
+			if MatchGlob(pattern, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
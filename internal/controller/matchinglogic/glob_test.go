@@ -0,0 +1,96 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Glob matching", func() {
+	DescribeTable("MatchGlob",
+		func(pattern, s string, expected bool) {
+			Expect(MatchGlob(pattern, s)).To(Equal(expected))
+		},
+		Entry("exact", "web", "web", true),
+		Entry("exact mismatch", "web", "web-1", false),
+		Entry("star suffix", "web-*", "web-frontend-1", true),
+		Entry("star matches empty", "web-*", "web-", true),
+		Entry("star spans slashes", "*/ml-*", "registry.io/team/ml-train", true),
+		Entry("question mark", "node-??", "node-01", true),
+		Entry("question mark needs a character", "node-??", "node-1", false),
+		Entry("backtracking", "*a*b", "xaxxab", true),
+		Entry("backtracking mismatch", "*a*b", "xaxxa", false),
+	)
+
+	DescribeTable("MatchImage",
+		func(pattern, image string, expected bool) {
+			Expect(MatchImage(pattern, image)).To(Equal(expected))
+		},
+		Entry("familiar name matches qualified image", "nginx", "docker.io/library/nginx:1.27", true),
+		Entry("qualified pattern matches familiar image", "docker.io/library/nginx", "nginx", true),
+		Entry("repository pattern", "library/nginx", "nginx:1.27", true),
+		Entry("short registry form", "docker.io/nginx", "nginx", true),
+		Entry("legacy index host", "nginx", "index.docker.io/library/nginx", true),
+		Entry("different repository", "nginx", "docker.io/bitnami/nginx:1.27", false),
+		Entry("user repository", "bitnami/nginx", "bitnami/nginx:1.27", true),
+		Entry("tag glob", "nginx:1.*", "nginx:1.27", true),
+		Entry("tag mismatch", "nginx:1.*", "nginx:2.0", false),
+		Entry("untagged image is latest", "nginx:latest", "nginx", true),
+		Entry("digest under a different registry", "app@sha256:*", "ghcr.io/org/app@sha256:abc", false),
+		Entry("digest with registry glob", "*/app@sha256:*", "ghcr.io/org/app@sha256:abc", true),
+		Entry("tag and digest", "*/app:v1", "ghcr.io/org/app:v1@sha256:abc", true),
+		Entry("registry port is not a tag", "localhost:5000/app", "localhost:5000/app:dev", true),
+		Entry("private registry glob", "*/ml-*", "registry.example.com/team/ml-train:3", true),
+		Entry("repository without registry", "team/ml-train", "registry.example.com/team/ml-train", true),
+		Entry("raw substring glob", "*nginx*", "quay.io/x/nginx-unprivileged:1", true),
+	)
+
+	It("Should match pod and node names with wildcards", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{
+			CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Name: "web-*"},
+			PodMatch:    &autolabellerv1alpha1.PodMatchCriteria{Images: []string{"redis", "nginx:1.*"}},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abc12"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "sidecar", Image: "busybox"},
+				{Name: "web", Image: "docker.io/library/nginx:1.27"},
+			}},
+		}
+		ok, fields := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElements("commonMatch.name", "podMatch.images:nginx:1.*"))
+
+		pod.Name = "api-abc12"
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node-1"}}
+		ok, _ = MatchesNodeDetailed(&autolabellerv1alpha1.MatchCriteria{
+			CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Name: "gpu-node-?"},
+		}, node, nil)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Should match images of init and ephemeral containers", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{Images: []string{"*/migrate"}}}
+		pod := &corev1.Pod{Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate", Image: "ghcr.io/org/migrate:v2"}},
+			Containers:     []corev1.Container{{Name: "web", Image: "nginx"}},
+		}}
+		ok, _ := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+
+		mc.PodMatch.Images = []string{"busybox"}
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+		pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name: "debug", Image: "busybox:1.36",
+		}}}
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -0,0 +1,80 @@
+package matchinglogic
+
+import (
+	"fmt"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// matchGroups evaluates the AnyOf, AllOf and Not groups of mc. match evaluates nested criteria against the same
+// target with the target kind's matcher, so groups nest to any depth. Matched fields of the branches that decided
+// the outcome are reported with their position, e.g. "anyOf[1].commonMatch.namespace".
+func matchGroups(mc *autolabellerv1alpha1.MatchCriteria, match func(*autolabellerv1alpha1.MatchCriteria) (bool, []string)) (bool, []string) {
+	matchedFields := []string{}
+	for i := range mc.AllOf {
+		ok, fields := match(&mc.AllOf[i])
+		if !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, branchFields(fmt.Sprintf("allOf[%d]", i), fields)...)
+	}
+	if len(mc.AnyOf) > 0 {
+		matched := false
+		for i := range mc.AnyOf {
+			if ok, fields := match(&mc.AnyOf[i]); ok {
+				matchedFields = append(matchedFields, branchFields(fmt.Sprintf("anyOf[%d]", i), fields)...)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, matchedFields
+		}
+	}
+	if mc.Not != nil {
+		if ok, _ := match(mc.Not); ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "not")
+	}
+	return true, matchedFields
+}
+
+// anyCriteria reports whether pred holds for mc or any criteria nested in it.
+func anyCriteria(mc *autolabellerv1alpha1.MatchCriteria, pred func(*autolabellerv1alpha1.MatchCriteria) bool) bool {
+	if mc == nil {
+		return false
+	}
+	if pred(mc) || anyCriteria(mc.Not, pred) {
+		return true
+	}
+	for _, group := range [][]autolabellerv1alpha1.MatchCriteria{mc.AnyOf, mc.AllOf} {
+		for i := range group {
+			if anyCriteria(&group[i], pred) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forEachCriteria calls fn for mc and every criteria nested in it.
+func forEachCriteria(mc *autolabellerv1alpha1.MatchCriteria, fn func(*autolabellerv1alpha1.MatchCriteria)) {
+	anyCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) bool {
+		fn(c)
+		return false
+	})
+}
+
+// branchFields prefixes the fields matched by a nested branch with its position. A branch without criteria still
+// reports its position so the decision stays visible.
+func branchFields(branch string, fields []string) []string {
+	if len(fields) == 0 {
+		return []string{branch}
+	}
+	prefixed := make([]string, 0, len(fields))
+	for _, f := range fields {
+		prefixed = append(prefixed, branch+"."+f)
+	}
+	return prefixed
+}
@@ -0,0 +1,135 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Match groups", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"tier": "core"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx:1.27"}}},
+		}
+	})
+
+	images := func(patterns ...string) autolabellerv1alpha1.MatchCriteria {
+		return autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{Images: patterns}}
+	}
+	namespace := func(ns string) autolabellerv1alpha1.MatchCriteria {
+		return autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Namespace: ns}}
+	}
+
+	It("Should report the anyOf branch that matched", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{AnyOf: []autolabellerv1alpha1.MatchCriteria{
+			images("redis:*"),
+			{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Labels: map[string]string{"tier": "core"}}},
+		}}
+		ok, fields := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(Equal([]string{"anyOf[1].commonMatch.labels[tier]"}))
+
+		pod.Labels = nil
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should require every allOf branch", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{AllOf: []autolabellerv1alpha1.MatchCriteria{images("nginx:*"), namespace("default")}}
+		ok, fields := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElements("allOf[0].podMatch.images:nginx:*", "allOf[1].commonMatch.namespace"))
+
+		mc.AllOf[1] = namespace("prod")
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should exclude targets matching not", func() {
+		kubeSystem := namespace("kube-system")
+		mc := &autolabellerv1alpha1.MatchCriteria{PodMatch: images("nginx:*").PodMatch, Not: &kubeSystem}
+		ok, fields := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("not"))
+
+		pod.Namespace = "kube-system"
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should nest groups", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{AnyOf: []autolabellerv1alpha1.MatchCriteria{
+			{AllOf: []autolabellerv1alpha1.MatchCriteria{images("redis:*"), namespace("default")}},
+			{Not: &autolabellerv1alpha1.MatchCriteria{AnyOf: []autolabellerv1alpha1.MatchCriteria{namespace("prod"), namespace("staging")}}},
+		}}
+		ok, fields := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(Equal([]string{"anyOf[1].not"}))
+	})
+
+	It("Should validate nested criteria against the target kind", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{AnyOf: []autolabellerv1alpha1.MatchCriteria{
+			{NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{OSLabels: []string{"linux"}}},
+			{Not: &autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{CPURequests: ">lots"}}},
+		}}
+		errs := ValidateMatchCriteria("", "Pod", mc, field.NewPath("match"))
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("match.anyOf[0].nodeMatch"))
+		Expect(errs[1].Field).To(Equal("match.anyOf[1].not.podMatch.cpuRequests"))
+	})
+
+	It("Should reject branches without criteria and enforce enums in nested criteria", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{
+			AllOf: []autolabellerv1alpha1.MatchCriteria{{}},
+			Not:   &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{Phase: "Deleting"}},
+		}
+		errs := ValidateMatchCriteria("", "Namespace", mc, field.NewPath("match"))
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("match.allOf[0]"))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+		Expect(errs[1].Field).To(Equal("match.not.namespaceMatch.phase"))
+		Expect(errs[1].Type).To(Equal(field.ErrorTypeNotSupported))
+	})
+
+	DescribeTable("Should enforce list item enums in nested criteria",
+		func(targetKind string, branch autolabellerv1alpha1.MatchCriteria, path string) {
+			mc := &autolabellerv1alpha1.MatchCriteria{AnyOf: []autolabellerv1alpha1.MatchCriteria{branch}}
+			errs := ValidateMatchCriteria("", targetKind, mc, field.NewPath("match"))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal(path))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		},
+		Entry("pod phases", "Pod", autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{
+			Status: &autolabellerv1alpha1.PodStatusCriteria{Phases: []string{"Running", "running"}},
+		}}, "match.anyOf[0].podMatch.status.phases[1]"),
+		Entry("pod QoS classes", "Pod", autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{
+			Status: &autolabellerv1alpha1.PodStatusCriteria{QOSClasses: []string{"Besteffort"}},
+		}}, "match.anyOf[0].podMatch.status.qosClasses[0]"),
+		Entry("service protocols", "Service", autolabellerv1alpha1.MatchCriteria{ServiceMatch: &autolabellerv1alpha1.ServiceMatchCriteria{
+			Protocols: []string{"TCP", "HTTP"},
+		}}, "match.anyOf[0].serviceMatch.protocols[1]"),
+	)
+
+	It("Should limit nesting depth", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{}
+		for range MaxMatchNesting + 1 {
+			mc = &autolabellerv1alpha1.MatchCriteria{Not: mc}
+		}
+		Expect(ValidateMatchCriteria("", "Pod", mc, field.NewPath("match"))).To(HaveLen(1))
+	})
+
+	It("Should look for ResourceQuota criteria in groups", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{Not: &autolabellerv1alpha1.MatchCriteria{
+			NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{HasResourceQuota: ptr.To(true)},
+		}}
+		Expect(NeedsQuotaNamespaces(mc)).To(BeTrue())
+	})
+})
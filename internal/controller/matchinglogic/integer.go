@@ -0,0 +1,74 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// IntExpression is a parsed integer comparison such as ">3", "==5", "!=0" or the inclusive range "2..4".
+// It is shared by every integer criterion (replicas, parallelism, restart counts).
+type IntExpression struct {
+	cmp Comparison
+}
+
+// ParseIntExpression parses expr and checks that its operands are base-10 integers.
+func ParseIntExpression(expr string) (IntExpression, error) {
+	cmp, err := ParseComparison(expr)
+	if err != nil {
+		return IntExpression{}, err
+	}
+	for _, operand := range []string{cmp.Value, cmp.Upper} {
+		if operand == "" {
+			continue
+		}
+		if _, err := strconv.ParseInt(operand, 10, 64); err != nil {
+			return IntExpression{}, fmt.Errorf("%q is not a valid integer", operand)
+		}
+	}
+	if err := cmp.checkRange(func(low, high string) int {
+		l, _ := strconv.ParseInt(low, 10, 64)
+		h, _ := strconv.ParseInt(high, 10, 64)
+		return compareOrdered(l, h)
+	}); err != nil {
+		return IntExpression{}, err
+	}
+	return IntExpression{cmp: cmp}, nil
+}
+
+// Matches reports whether v satisfies the expression.
+func (e IntExpression) Matches(v int64) bool {
+	ok, _ := e.cmp.evaluate(func(operand string) (int, error) {
+		n, err := strconv.ParseInt(operand, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case v < n:
+			return -1, nil
+		case v > n:
+			return 1, nil
+		}
+		return 0, nil
+	})
+	return ok
+}
+
+// MatchInt reports whether v satisfies the integer expression expr.
+func MatchInt(expr string, v int64) (bool, error) {
+	e, err := ParseIntExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	return e.Matches(v), nil
+}
+
+// matchReplicas evaluates expr against a workload's desired replicas, which default to 1 when unset.
+// It returns the replica count that was compared.
+func matchReplicas(expr string, replicas *int32) (bool, int32) {
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+	ok, err := MatchInt(expr, int64(desired))
+	return err == nil && ok, desired
+}
@@ -0,0 +1,61 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Integer expressions", func() {
+	DescribeTable("MatchInt",
+		func(expr string, v int, expected bool) {
+			ok, err := MatchInt(expr, int64(v))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("bare number", "3", 3, true),
+		Entry("bare number mismatch", "3", 4, false),
+		Entry("greater than", ">3", 4, true),
+		Entry("greater than boundary", ">3", 3, false),
+		Entry("equal", "==5", 5, true),
+		Entry("single equals", "=5", 5, true),
+		Entry("not equal", "!=0", 0, false),
+		Entry("less or equal with spaces", " <= 2 ", 2, true),
+		Entry("range lower bound", "2..4", 2, true),
+		Entry("range upper bound", "2..4", 4, true),
+		Entry("range outside", "2..4", 5, false),
+		Entry("negative operand", ">-1", 0, true),
+	)
+
+	DescribeTable("Malformed expressions",
+		func(expr string) {
+			_, err := ParseIntExpression(expr)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("word", ">three"),
+		Entry("fraction", "1.5"),
+		Entry("missing operand", ">="),
+		Entry("double operator", ">>3"),
+		Entry("open range", "2.."),
+		Entry("inverted range", "4..2"),
+	)
+
+	It("Should compare Deployment replicas", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{
+			DeploymentMatch: &autolabellerv1alpha1.DeploymentMatchCriteria{Replicas: ">3"},
+		}
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](5)}}
+		ok, fields := MatchesDeploymentDetailed(mc, deployment, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("deploymentMatch.replicas:5"))
+
+		By("defaulting unset replicas to 1")
+		deployment.Spec.Replicas = nil
+		ok, _ = MatchesDeploymentDetailed(mc, deployment, nil)
+		Expect(ok).To(BeFalse())
+	})
+})
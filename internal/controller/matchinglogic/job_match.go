@@ -0,0 +1,139 @@
+package matchinglogic
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// Job outcomes for JobMatchCriteria.Status
+const (
+	JobStatusActive   = "Active"
+	JobStatusComplete = "Complete"
+	JobStatusFailed   = "Failed"
+)
+
+// defaultBackoffLimit is the backoffLimit the API server applies when a Job does not set one.
+const defaultBackoffLimit = 6
+
+// MatchesJobDetailed returns whether the Job matches and a list of fields that matched.
+// PodMatch criteria are evaluated against the pod template.
+func MatchesJobDetailed(mc *autolabellerv1alpha1.MatchCriteria, job *batchv1.Job, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, job, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if jm := mc.JobMatch; jm != nil {
+		ok, specFields := matchJobSpec(jm, &job.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+		if jm.Status != "" {
+			if jobStatus(job) != jm.Status {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "jobMatch.status")
+		}
+	}
+
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &job.Spec.Template.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesJobDetailed(sub, job, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
+
+// matchJobSpec evaluates the spec criteria of jm against a Job spec. It is shared by Jobs and by CronJobs,
+// which are matched through their job template.
+func matchJobSpec(jm *autolabellerv1alpha1.JobMatchCriteria, spec *batchv1.JobSpec) (bool, []string) {
+	matchedFields := []string{}
+	backoffLimit := int32(defaultBackoffLimit)
+	if spec.BackoffLimit != nil {
+		backoffLimit = *spec.BackoffLimit
+	}
+	parallelism := int32(1)
+	if spec.Parallelism != nil {
+		parallelism = *spec.Parallelism
+	}
+	var ttl *int64
+	if spec.TTLSecondsAfterFinished != nil {
+		ttl = ptr.To(int64(*spec.TTLSecondsAfterFinished))
+	}
+	var completions *int64
+	if spec.Completions != nil {
+		completions = ptr.To(int64(*spec.Completions))
+	}
+
+	criteria := []struct {
+		field, expr string
+		value       *int64
+	}{
+		{"jobMatch.backoffLimit", jm.BackoffLimit, ptr.To(int64(backoffLimit))},
+		{"jobMatch.completions", jm.Completions, completions},
+		{"jobMatch.parallelism", jm.Parallelism, ptr.To(int64(parallelism))},
+		{"jobMatch.activeDeadlineSeconds", jm.ActiveDeadlineSeconds, spec.ActiveDeadlineSeconds},
+		{"jobMatch.ttlSecondsAfterFinished", jm.TTLSecondsAfterFinished, ttl},
+	}
+	for _, c := range criteria {
+		if c.expr == "" {
+			continue
+		}
+		// Unset optional values never match an expression
+		if c.value == nil {
+			return false, matchedFields
+		}
+		if ok, err := MatchInt(c.expr, *c.value); err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("%s:%d", c.field, *c.value))
+	}
+
+	if jm.Suspend != nil {
+		suspended := spec.Suspend != nil && *spec.Suspend
+		if suspended != *jm.Suspend {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "jobMatch.suspend")
+	}
+	return true, matchedFields
+}
+
+// jobStatus reports the Job's outcome from its terminal conditions.
+func jobStatus(job *batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return JobStatusComplete
+		case batchv1.JobFailed:
+			return JobStatusFailed
+		}
+	}
+	return JobStatusActive
+}
@@ -0,0 +1,139 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// Operators for MetadataRequirement.Operator
+const (
+	MetadataOpExists       = "Exists"
+	MetadataOpDoesNotExist = "DoesNotExist"
+	MetadataOpIn           = "In"
+	MetadataOpNotIn        = "NotIn"
+	MetadataOpPrefix       = "Prefix"
+	MetadataOpSuffix       = "Suffix"
+	MetadataOpGlob         = "Glob"
+	MetadataOpRegex        = "Regex"
+)
+
+var metadataOperators = []string{MetadataOpExists, MetadataOpDoesNotExist, MetadataOpIn, MetadataOpNotIn,
+	MetadataOpPrefix, MetadataOpSuffix, MetadataOpGlob, MetadataOpRegex}
+
+// annotationRegexPrefix marks CommonMatch.Annotations values that are regular expressions.
+const annotationRegexPrefix = "regex:"
+
+// matchMetadataRequirement reports whether the labels or annotations in m satisfy req.
+func matchMetadataRequirement(req autolabellerv1alpha1.MetadataRequirement, m map[string]string) bool {
+	value, ok := m[req.Key]
+	switch req.Operator {
+	case MetadataOpExists:
+		return ok
+	case MetadataOpDoesNotExist:
+		return !ok
+	case MetadataOpNotIn:
+		return !ok || !slices.Contains(req.Values, value)
+	}
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(req.Values, func(operand string) bool {
+		switch req.Operator {
+		case MetadataOpIn:
+			return value == operand
+		case MetadataOpPrefix:
+			return strings.HasPrefix(value, operand)
+		case MetadataOpSuffix:
+			return strings.HasSuffix(value, operand)
+		case MetadataOpGlob:
+			return MatchGlob(operand, value)
+		case MetadataOpRegex:
+			re, err := compileAnchored(operand)
+			return err == nil && re.MatchString(value)
+		}
+		return false
+	})
+}
+
+// matchAnnotationValue reports whether an annotation value satisfies a CommonMatch.Annotations entry: an exact value,
+// or a regular expression after the "regex:" prefix.
+func matchAnnotationValue(expected, value string) bool {
+	if pattern, ok := strings.CutPrefix(expected, annotationRegexPrefix); ok {
+		re, err := compileAnchored(pattern)
+		return err == nil && re.MatchString(value)
+	}
+	return value == expected
+}
+
+// LabelSelectorRequirements converts the label requirements that label selectors support (Exists, DoesNotExist,
+// In and NotIn) so they can be evaluated by the API server. Other requirements are left to in-memory matching.
+func LabelSelectorRequirements(reqs []autolabellerv1alpha1.MetadataRequirement) []labels.Requirement {
+	ops := map[string]selection.Operator{
+		MetadataOpExists:       selection.Exists,
+		MetadataOpDoesNotExist: selection.DoesNotExist,
+		MetadataOpIn:           selection.In,
+		MetadataOpNotIn:        selection.NotIn,
+	}
+	var requirements []labels.Requirement
+	for _, req := range reqs {
+		op, ok := ops[req.Operator]
+		if !ok {
+			continue
+		}
+		var values []string
+		if op == selection.In || op == selection.NotIn {
+			values = req.Values
+		}
+		if r, err := labels.NewRequirement(req.Key, op, values); err == nil {
+			requirements = append(requirements, *r)
+		}
+	}
+	return requirements
+}
+
+// validateMetadataRequirements checks keys, operators and operands. Values of label In and NotIn requirements must
+// be valid label values, as they are sent to the API server.
+func validateMetadataRequirements(reqs []autolabellerv1alpha1.MetadataRequirement, isLabel bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, req := range reqs {
+		p := fldPath.Index(i)
+		for _, msg := range validation.IsQualifiedName(req.Key) {
+			allErrs = append(allErrs, field.Invalid(p.Child("key"), req.Key, msg))
+		}
+		switch req.Operator {
+		case MetadataOpExists, MetadataOpDoesNotExist:
+			if len(req.Values) > 0 {
+				allErrs = append(allErrs, field.Forbidden(p.Child("values"), fmt.Sprintf("not used by the %s operator", req.Operator)))
+			}
+			continue
+		case MetadataOpIn, MetadataOpNotIn, MetadataOpPrefix, MetadataOpSuffix, MetadataOpGlob, MetadataOpRegex:
+		default:
+			allErrs = append(allErrs, field.NotSupported(p.Child("operator"), req.Operator, metadataOperators))
+			continue
+		}
+		if len(req.Values) == 0 {
+			allErrs = append(allErrs, field.Required(p.Child("values"), fmt.Sprintf("the %s operator needs at least one value", req.Operator)))
+		}
+		for j, v := range req.Values {
+			switch {
+			case req.Operator == MetadataOpRegex:
+				if _, err := compileAnchored(v); err != nil {
+					allErrs = append(allErrs, field.Invalid(p.Child("values").Index(j), v, err.Error()))
+				}
+			case isLabel && (req.Operator == MetadataOpIn || req.Operator == MetadataOpNotIn):
+				for _, msg := range validation.IsValidLabelValue(v) {
+					allErrs = append(allErrs, field.Invalid(p.Child("values").Index(j), v, msg))
+				}
+			}
+		}
+	}
+	return allErrs
+}
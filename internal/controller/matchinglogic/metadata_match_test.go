@@ -0,0 +1,113 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Metadata requirements", func() {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "api-7d9f",
+		Namespace:   "default",
+		Labels:      map[string]string{"app": "api", "release": "v1.4.2", "tier": "backend"},
+		Annotations: map[string]string{"owner": "team-payments", "ci.example.com/build": "1234"},
+	}}
+	requiring := func(labels, annotations []autolabellerv1alpha1.MetadataRequirement) *autolabellerv1alpha1.MatchCriteria {
+		return &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{
+			LabelRequirements:      labels,
+			AnnotationRequirements: annotations,
+		}}
+	}
+	req := func(key, op string, values ...string) autolabellerv1alpha1.MetadataRequirement {
+		return autolabellerv1alpha1.MetadataRequirement{Key: key, Operator: op, Values: values}
+	}
+
+	DescribeTable("Should evaluate label operators",
+		func(r autolabellerv1alpha1.MetadataRequirement, expected bool) {
+			ok, _ := MatchesPodDetailed(requiring([]autolabellerv1alpha1.MetadataRequirement{r}, nil), pod, nil)
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("Exists", req("app", MetadataOpExists), true),
+		Entry("Exists on a missing key", req("team", MetadataOpExists), false),
+		Entry("DoesNotExist", req("team", MetadataOpDoesNotExist), true),
+		Entry("In", req("tier", MetadataOpIn, "frontend", "backend"), true),
+		Entry("In on a missing key", req("team", MetadataOpIn, "payments"), false),
+		Entry("NotIn", req("tier", MetadataOpNotIn, "frontend"), true),
+		Entry("NotIn on a missing key", req("team", MetadataOpNotIn, "payments"), true),
+		Entry("NotIn on a listed value", req("tier", MetadataOpNotIn, "backend"), false),
+		Entry("Prefix", req("release", MetadataOpPrefix, "v2.", "v1."), true),
+		Entry("Suffix", req("app", MetadataOpSuffix, "-api"), false),
+		Entry("Glob", req("release", MetadataOpGlob, "v1.?.*"), true),
+		Entry("Regex", req("release", MetadataOpRegex, `v1\.\d+\.\d+`), true),
+		Entry("Regex is anchored", req("release", MetadataOpRegex, `1\.4`), false),
+	)
+
+	It("Should require every annotation requirement and report the matched keys", func() {
+		mc := requiring(nil, []autolabellerv1alpha1.MetadataRequirement{
+			req("owner", MetadataOpRegex, "team-[a-z]+"),
+			req("ci.example.com/build", MetadataOpExists),
+		})
+		ok, fields := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElements("commonMatch.annotationRequirements[owner]",
+			"commonMatch.annotationRequirements[ci.example.com/build]"))
+
+		mc.CommonMatch.AnnotationRequirements = append(mc.CommonMatch.AnnotationRequirements, req("owner", MetadataOpPrefix, "svc-"))
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should accept regex: values in annotations", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{
+			Annotations: map[string]string{"owner": "regex:team-(payments|search)"},
+		}}
+		ok, _ := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		mc.CommonMatch.Annotations["owner"] = "regex:team"
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should push only selector operators to the API server", func() {
+		reqs := LabelSelectorRequirements([]autolabellerv1alpha1.MetadataRequirement{
+			req("app", MetadataOpExists),
+			req("tier", MetadataOpNotIn, "frontend"),
+			req("release", MetadataOpRegex, "v1.*"),
+		})
+		Expect(reqs).To(HaveLen(2))
+		Expect(reqs[0].String()).To(Equal("app"))
+		Expect(reqs[1].String()).To(Equal("tier notin (frontend)"))
+	})
+
+	It("Should reject invalid requirements", func() {
+		mc := requiring([]autolabellerv1alpha1.MetadataRequirement{
+			req("app", MetadataOpExists, "api"),
+			req("tier", MetadataOpIn),
+			req("tier", MetadataOpIn, "not a label value"),
+			req("release", "Matches", "v1"),
+		}, []autolabellerv1alpha1.MetadataRequirement{
+			req("owner", MetadataOpRegex, "team-("),
+			req("not a key", MetadataOpExists),
+		})
+		mc.CommonMatch.Annotations = map[string]string{"owner": "regex:["}
+		errs := ValidateMatchCriteria("", "Pod", mc, field.NewPath("match"))
+		var paths []string
+		for _, err := range errs {
+			paths = append(paths, err.Field)
+		}
+		Expect(paths).To(ConsistOf(
+			"match.commonMatch.labelRequirements[0].values",
+			"match.commonMatch.labelRequirements[1].values",
+			"match.commonMatch.labelRequirements[2].values[0]",
+			"match.commonMatch.labelRequirements[3].operator",
+			"match.commonMatch.annotationRequirements[0].values[0]",
+			"match.commonMatch.annotationRequirements[1].key",
+			"match.commonMatch.annotations[owner]",
+		))
+	})
+})
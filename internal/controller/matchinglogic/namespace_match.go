@@ -0,0 +1,92 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesNamespaceDetailed returns whether the namespace matches and a list of fields that matched.
+// env supplies the ResourceQuota presence needed by NamespaceMatch.HasResourceQuota.
+func MatchesNamespaceDetailed(mc *autolabellerv1alpha1.MatchCriteria, ns *corev1.Namespace, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, ns, true, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if nm := mc.NamespaceMatch; nm != nil {
+		if nm.Phase != "" {
+			// Namespaces created before the phase was populated are Active
+			phase := ns.Status.Phase
+			if phase == "" {
+				phase = corev1.NamespaceActive
+			}
+			if string(phase) != nm.Phase {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "namespaceMatch.phase")
+		}
+		for _, key := range nm.HasLabels {
+			if _, ok := ns.Labels[key]; !ok {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("namespaceMatch.hasLabels[%s]", key))
+		}
+		if nm.HasResourceQuota != nil {
+			has := false
+			if env != nil {
+				_, has = env.QuotaNamespaces[ns.Name]
+			}
+			if has != *nm.HasResourceQuota {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "namespaceMatch.hasResourceQuota")
+		}
+		if nm.Age != "" {
+			age := time.Since(ns.CreationTimestamp.Time)
+			if ok, err := MatchDuration(nm.Age, age); err != nil || !ok {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("namespaceMatch.age:%s", age.Truncate(time.Second)))
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesNamespaceDetailed(sub, ns, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
+
+// NeedsQuotaNamespaces reports whether evaluating mc requires Environment.QuotaNamespaces.
+func NeedsQuotaNamespaces(mc *autolabellerv1alpha1.MatchCriteria) bool {
+	return anyCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) bool {
+		return c.NamespaceMatch != nil && c.NamespaceMatch.HasResourceQuota != nil
+	})
+}
+
+// NamespaceRecheckAfter returns how long until an age criterion in mc may change its outcome for ns, so the rule
+// can be re-evaluated without a Namespace event. It returns 0 when no such change is due.
+func NamespaceRecheckAfter(mc *autolabellerv1alpha1.MatchCriteria, ns *corev1.Namespace, now time.Time) time.Duration {
+	age := now.Sub(ns.CreationTimestamp.Time)
+	var next time.Duration
+	forEachCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) {
+		if c.NamespaceMatch != nil && c.NamespaceMatch.Age != "" {
+			next = Sooner(next, durationRecheckAfter(c.NamespaceMatch.Age, age))
+		}
+	})
+	return next
+}
@@ -0,0 +1,100 @@
+package matchinglogic
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Namespace matching", func() {
+	var ns *corev1.Namespace
+
+	BeforeEach(func() {
+		ns = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "team-payments",
+				Labels:            map[string]string{"owner": "payments"},
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+			},
+			Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		}
+	})
+
+	match := func(mc *autolabellerv1alpha1.MatchCriteria, env *Environment) bool {
+		ok, _ := MatchesNamespaceDetailed(mc, ns, env)
+		return ok
+	}
+
+	It("Should match names with wildcards", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Name: "team-*"}}
+		Expect(match(mc, nil)).To(BeTrue())
+		ns.Name = "kube-system"
+		Expect(match(mc, nil)).To(BeFalse())
+	})
+
+	It("Should match phase and label presence", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{
+			Phase:     "Active",
+			HasLabels: []string{"owner"},
+		}}
+		Expect(match(mc, nil)).To(BeTrue())
+		mc.NamespaceMatch.HasLabels = append(mc.NamespaceMatch.HasLabels, "cost-center")
+		Expect(match(mc, nil)).To(BeFalse())
+		mc.NamespaceMatch.HasLabels = nil
+		ns.Status.Phase = corev1.NamespaceTerminating
+		Expect(match(mc, nil)).To(BeFalse())
+	})
+
+	It("Should match ResourceQuota presence from the environment", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{
+			HasResourceQuota: ptr.To(true),
+		}}
+		Expect(NeedsQuotaNamespaces(mc)).To(BeTrue())
+		Expect(match(mc, &Environment{})).To(BeFalse())
+		env := &Environment{QuotaNamespaces: map[string]struct{}{"team-payments": {}}}
+		Expect(match(mc, env)).To(BeTrue())
+		mc.NamespaceMatch.HasResourceQuota = ptr.To(false)
+		Expect(match(mc, env)).To(BeFalse())
+	})
+
+	It("Should compare namespace age", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{Age: ">=24h"}}
+		Expect(match(mc, nil)).To(BeTrue())
+		mc.NamespaceMatch.Age = "<1h"
+		Expect(match(mc, nil)).To(BeFalse())
+		mc.NamespaceMatch.Age = "24h..72h"
+		Expect(match(mc, nil)).To(BeTrue())
+	})
+
+	It("Should reject an inverted age range", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{Age: "72h..24h"}}
+		errs := ValidateMatchCriteria("", "Namespace", mc, field.NewPath("match"))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("match.namespaceMatch.age"))
+		Expect(errs[0].Detail).To(ContainSubstring("lower bound exceeds upper bound"))
+	})
+
+	It("Should schedule a recheck when the namespace age crosses a threshold", func() {
+		now := time.Now()
+		ns.CreationTimestamp = metav1.NewTime(now.Add(-20 * time.Minute))
+		mc := &autolabellerv1alpha1.MatchCriteria{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{Age: "<30m"}}
+		Expect(match(mc, nil)).To(BeTrue())
+		Expect(NamespaceRecheckAfter(mc, ns, now)).To(Equal(10*time.Minute + time.Second))
+
+		mc = &autolabellerv1alpha1.MatchCriteria{AllOf: []autolabellerv1alpha1.MatchCriteria{
+			{NamespaceMatch: &autolabellerv1alpha1.NamespaceMatchCriteria{Age: "1h..2h"}},
+		}}
+		Expect(match(mc, nil)).To(BeFalse())
+		Expect(NamespaceRecheckAfter(mc, ns, now)).To(Equal(40*time.Minute + time.Second))
+
+		ns.CreationTimestamp = metav1.NewTime(now.Add(-3 * time.Hour))
+		Expect(NamespaceRecheckAfter(mc, ns, now)).To(BeZero(), "every threshold has already passed")
+	})
+})
@@ -0,0 +1,53 @@
+package matchinglogic
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// NeedsNamespaceLabels reports whether evaluating mc requires Environment.SelectedNamespaces.
+func NeedsNamespaceLabels(mc *autolabellerv1alpha1.MatchCriteria) bool {
+	return anyCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) bool {
+		return c.CommonMatch != nil && c.CommonMatch.NamespaceSelector != nil
+	})
+}
+
+// SelectNamespaces resolves every namespaceSelector in mc, including nested ones, against namespaces.
+// The result is meant for Environment.SelectedNamespaces. Invalid selectors select nothing.
+func SelectNamespaces(mc *autolabellerv1alpha1.MatchCriteria, namespaces []corev1.Namespace) map[string]map[string]struct{} {
+	selected := map[string]map[string]struct{}{}
+	forEachCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) {
+		if c.CommonMatch == nil || c.CommonMatch.NamespaceSelector == nil {
+			return
+		}
+		key := metav1.FormatLabelSelector(c.CommonMatch.NamespaceSelector)
+		if _, ok := selected[key]; ok {
+			return
+		}
+		names := map[string]struct{}{}
+		selected[key] = names
+		selector, err := metav1.LabelSelectorAsSelector(c.CommonMatch.NamespaceSelector)
+		if err != nil {
+			return
+		}
+		for _, ns := range namespaces {
+			if selector.Matches(labels.Set(ns.Labels)) {
+				names[ns.Name] = struct{}{}
+			}
+		}
+	})
+	return selected
+}
+
+// namespaceSelected reports whether selector selects the namespace. Selectors that were not resolved into
+// the environment select nothing.
+func (env *Environment) namespaceSelected(selector *metav1.LabelSelector, namespace string) bool {
+	if env == nil {
+		return false
+	}
+	_, ok := env.SelectedNamespaces[metav1.FormatLabelSelector(selector)][namespace]
+	return ok
+}
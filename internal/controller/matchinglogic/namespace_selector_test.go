@@ -0,0 +1,80 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Namespace selectors", func() {
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"tier": "core", "team": "payments"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{"tier": "edge"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sandbox"}},
+	}
+	selecting := func(selector *metav1.LabelSelector) *autolabellerv1alpha1.MatchCriteria {
+		return &autolabellerv1alpha1.MatchCriteria{CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{NamespaceSelector: selector}}
+	}
+	deployment := func(namespace string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: namespace}}
+	}
+
+	It("Should match targets by the labels of their namespace", func() {
+		mc := selecting(&metav1.LabelSelector{MatchLabels: map[string]string{"tier": "core"}})
+		Expect(NeedsNamespaceLabels(mc)).To(BeTrue())
+		env := &Environment{SelectedNamespaces: SelectNamespaces(mc, namespaces)}
+
+		ok, fields := MatchesDeploymentDetailed(mc, deployment("payments"), env)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("commonMatch.namespaceSelector"))
+		ok, _ = MatchesDeploymentDetailed(mc, deployment("search"), env)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should support match expressions", func() {
+		mc := selecting(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpDoesNotExist},
+		}})
+		env := &Environment{SelectedNamespaces: SelectNamespaces(mc, namespaces)}
+		ok, _ := MatchesDeploymentDetailed(mc, deployment("sandbox"), env)
+		Expect(ok).To(BeTrue())
+		ok, _ = MatchesDeploymentDetailed(mc, deployment("payments"), env)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should resolve selectors nested in groups", func() {
+		mc := &autolabellerv1alpha1.MatchCriteria{Not: selecting(&metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}})}
+		Expect(NeedsNamespaceLabels(mc)).To(BeTrue())
+		env := &Environment{SelectedNamespaces: SelectNamespaces(mc, namespaces)}
+		ok, _ := MatchesDeploymentDetailed(mc, deployment("payments"), env)
+		Expect(ok).To(BeTrue())
+		ok, _ = MatchesDeploymentDetailed(mc, deployment("search"), env)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should not match when namespaces were not resolved", func() {
+		mc := selecting(&metav1.LabelSelector{})
+		ok, _ := MatchesDeploymentDetailed(mc, deployment("payments"), nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should be ignored for cluster-scoped targets", func() {
+		mc := selecting(&metav1.LabelSelector{MatchLabels: map[string]string{"tier": "core"}})
+		ok, _ := MatchesNodeDetailed(mc, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}, nil)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Should reject invalid selectors", func() {
+		mc := selecting(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+		}})
+		errs := ValidateMatchCriteria("", "Deployment", mc, field.NewPath("match"))
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errs[0].Field).To(HavePrefix("match.commonMatch.namespaceSelector"))
+	})
+})
@@ -2,6 +2,7 @@ package matchinglogic
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -10,32 +11,39 @@ import (
 )
 
 // MatchesNodeDetailed returns whether the node matches and a list of fields that matched.
-// Note: CommonMatch.Labels and NodeMatch.ArchLabels/OSLabels are pre-filtered by FilterNodeList,
-// so we only check items that require in-memory inspection (name patterns, annotations, taints, kernel, runtime).
-func MatchesNodeDetailed(mc *autolabellerv1alpha1.MatchCriteria, node *corev1.Node) (bool, []string) {
+func MatchesNodeDetailed(mc *autolabellerv1alpha1.MatchCriteria, node *corev1.Node, env *Environment) (bool, []string) {
 	matchedFields := []string{}
 	if mc == nil {
 		return true, matchedFields
 	}
 
-	if cm := mc.CommonMatch; cm != nil {
-		// Labels are already pre-filtered by FilterNodeList, skip them here
-		if name := cm.Name; name != "" {
-			if node.Name != name {
+	ok, commonFields := matchShared(mc, node, true, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if nm := mc.NodeMatch; nm != nil {
+		// Architecture and OS labels match any of the listed values
+		if len(nm.ArchLabels) > 0 {
+			if !slices.Contains(nm.ArchLabels, node.Labels["kubernetes.io/arch"]) {
 				return false, matchedFields
 			}
-			matchedFields = append(matchedFields, "commonMatch.name")
+			matchedFields = append(matchedFields, "nodeMatch.archLabels")
 		}
-		for k, v := range cm.Annotations {
-			if node.Annotations[k] != v {
+		if len(nm.OSLabels) > 0 {
+			if !slices.Contains(nm.OSLabels, node.Labels["kubernetes.io/os"]) {
 				return false, matchedFields
 			}
-			matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.annotations[%s]", k))
+			matchedFields = append(matchedFields, "nodeMatch.osLabels")
 		}
-	}
 
-	if nm := mc.NodeMatch; nm != nil {
-		// Architecture and OS labels are already pre-filtered by FilterNodeList, skip them here
+		// Topology, role, legacy labels and node state
+		ok, stateFields := matchNodeState(nm, node)
+		matchedFields = append(matchedFields, stateFields...)
+		if !ok {
+			return false, matchedFields
+		}
 
 		// Taints match (expects key=value:effect)
 		if len(nm.Taints) > 0 {
@@ -51,12 +59,23 @@ func MatchesNodeDetailed(mc *autolabellerv1alpha1.MatchCriteria, node *corev1.No
 			}
 		}
 
-		// Kernel version (simple contains/equality match)
-		if nm.KernelVersion != "" {
-			if !strings.Contains(node.Status.NodeInfo.KernelVersion, nm.KernelVersion) {
-				return false, matchedFields
-			}
-			matchedFields = append(matchedFields, "nodeMatch.kernelVersion")
+		// Kernel, container runtime and kubelet versions
+		ok, versionFields := matchNodeVersions(nm, node)
+		matchedFields = append(matchedFields, versionFields...)
+		if !ok {
+			return false, matchedFields
+		}
+
+		// Capacity and allocatable resources
+		ok, capacityFields := matchNodeResources("capacity", nm.Capacity, node.Status.Capacity)
+		matchedFields = append(matchedFields, capacityFields...)
+		if !ok {
+			return false, matchedFields
+		}
+		ok, allocatableFields := matchNodeResources("allocatable", nm.Allocatable, node.Status.Allocatable)
+		matchedFields = append(matchedFields, allocatableFields...)
+		if !ok {
+			return false, matchedFields
 		}
 
 		// Container runtime (contains match against runtime version string)
@@ -68,5 +87,99 @@ func MatchesNodeDetailed(mc *autolabellerv1alpha1.MatchCriteria, node *corev1.No
 		}
 	}
 
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesNodeDetailed(sub, node, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
 	return true, matchedFields
 }
+
+// Node labels read by NodeMatch criteria besides kubernetes.io/arch and kubernetes.io/os
+const (
+	LabelControlPlane       = "node-role.kubernetes.io/control-plane"
+	LabelLegacyControlPlane = "node-role.kubernetes.io/master"
+	LabelLegacyArch         = "beta.kubernetes.io/arch"
+	LabelLegacyOS           = "beta.kubernetes.io/os"
+)
+
+// matchNodeState evaluates the topology, role and legacy label criteria of nm as well as the node's
+// schedulability, conditions and provider ID.
+func matchNodeState(nm *autolabellerv1alpha1.NodeMatchCriteria, node *corev1.Node) (bool, []string) {
+	matchedFields := []string{}
+	labelSets := []struct {
+		name, key string
+		values    []string
+	}{
+		{"legacyArchLabels", LabelLegacyArch, nm.LegacyArchLabels},
+		{"legacyOSLabels", LabelLegacyOS, nm.LegacyOSLabels},
+		{"zones", corev1.LabelTopologyZone, nm.Zones},
+		{"regions", corev1.LabelTopologyRegion, nm.Regions},
+	}
+	for _, set := range labelSets {
+		if len(set.values) == 0 {
+			continue
+		}
+		value, ok := node.Labels[set.key]
+		if !ok || !slices.Contains(set.values, value) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("nodeMatch.%s:%s", set.name, value))
+	}
+
+	if nm.ControlPlane != nil {
+		_, controlPlane := node.Labels[LabelControlPlane]
+		_, legacy := node.Labels[LabelLegacyControlPlane]
+		if (controlPlane || legacy) != *nm.ControlPlane {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "nodeMatch.controlPlane")
+	}
+	if nm.Unschedulable != nil {
+		if node.Spec.Unschedulable != *nm.Unschedulable {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "nodeMatch.unschedulable")
+	}
+	for _, want := range nm.Conditions {
+		condType, status, err := ParseNodeCondition(want)
+		if err != nil {
+			return false, matchedFields
+		}
+		actual := corev1.ConditionUnknown
+		if i := slices.IndexFunc(node.Status.Conditions, func(c corev1.NodeCondition) bool { return c.Type == condType }); i >= 0 {
+			actual = node.Status.Conditions[i].Status
+		}
+		if actual != status {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("nodeMatch.conditions:%s=%s", condType, status))
+	}
+	if len(nm.ProviderIDPrefixes) > 0 {
+		if !slices.ContainsFunc(nm.ProviderIDPrefixes, func(prefix string) bool { return strings.HasPrefix(node.Spec.ProviderID, prefix) }) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "nodeMatch.providerIDPrefixes")
+	}
+	return true, matchedFields
+}
+
+// ParseNodeCondition parses a "Type=Status" condition criterion; a bare type means "Type=True".
+func ParseNodeCondition(s string) (corev1.NodeConditionType, corev1.ConditionStatus, error) {
+	condType, status, found := strings.Cut(s, "=")
+	condType = strings.TrimSpace(condType)
+	if condType == "" {
+		return "", "", fmt.Errorf("condition %q must have the form Type=Status", s)
+	}
+	if !found {
+		return corev1.NodeConditionType(condType), corev1.ConditionTrue, nil
+	}
+	switch cs := corev1.ConditionStatus(strings.TrimSpace(status)); cs {
+	case corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown:
+		return corev1.NodeConditionType(condType), cs, nil
+	}
+	return "", "", fmt.Errorf("condition status %q must be True, False or Unknown", strings.TrimSpace(status))
+}
@@ -0,0 +1,79 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Node state criteria", func() {
+	var node *corev1.Node
+	matches := func(nm autolabellerv1alpha1.NodeMatchCriteria) bool {
+		ok, _ := MatchesNodeDetailed(&autolabellerv1alpha1.MatchCriteria{NodeMatch: &nm}, node, nil)
+		return ok
+	}
+
+	BeforeEach(func() {
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "cp-1", Labels: map[string]string{
+				"node-role.kubernetes.io/master": "",
+				"beta.kubernetes.io/arch":        "arm64",
+				"beta.kubernetes.io/os":          "linux",
+				"topology.kubernetes.io/zone":    "eu-west-1a",
+				"topology.kubernetes.io/region":  "eu-west-1",
+			}},
+			Spec: corev1.NodeSpec{ProviderID: "aws:///eu-west-1a/i-0abc", Unschedulable: true},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+			}},
+		}
+	})
+
+	It("Should match conditions, treating missing ones as Unknown", func() {
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{Conditions: []string{"Ready=False", "MemoryPressure"}})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{Conditions: []string{"DiskPressure=True"}})).To(BeFalse())
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{Conditions: []string{"DiskPressure=Unknown"}})).To(BeTrue())
+	})
+
+	It("Should match cordoned control-plane nodes, including the legacy role label", func() {
+		ok, fields := MatchesNodeDetailed(&autolabellerv1alpha1.MatchCriteria{NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{
+			ControlPlane:  ptr.To(true),
+			Unschedulable: ptr.To(true),
+		}}, node, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ConsistOf("nodeMatch.controlPlane", "nodeMatch.unschedulable"))
+		delete(node.Labels, "node-role.kubernetes.io/master")
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{ControlPlane: ptr.To(false)})).To(BeTrue())
+	})
+
+	It("Should match provider ID prefixes, topology and legacy labels", func() {
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{
+			ProviderIDPrefixes: []string{"gce://", "aws://"},
+			Zones:              []string{"eu-west-1a"},
+			Regions:            []string{"eu-west-1"},
+			LegacyArchLabels:   []string{"amd64", "arm64"},
+			LegacyOSLabels:     []string{"linux"},
+		})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{ProviderIDPrefixes: []string{"azure://"}})).To(BeFalse())
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{Regions: []string{"us-east-1"}})).To(BeFalse())
+		Expect(matches(autolabellerv1alpha1.NodeMatchCriteria{ArchLabels: []string{"arm64"}})).To(BeFalse(), "only the legacy arch label is set")
+	})
+
+	It("Should reject malformed conditions and label values", func() {
+		errs := ValidateMatchCriteria("", "Node", &autolabellerv1alpha1.MatchCriteria{NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{
+			Conditions: []string{"Ready=Maybe", "=True"},
+			Zones:      []string{"not a zone"},
+		}}, field.NewPath("match"))
+		var paths []string
+		for _, err := range errs {
+			paths = append(paths, err.Field)
+		}
+		Expect(paths).To(ConsistOf("match.nodeMatch.conditions[0]", "match.nodeMatch.conditions[1]", "match.nodeMatch.zones[0]"))
+	})
+})
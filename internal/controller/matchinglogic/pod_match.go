@@ -2,6 +2,8 @@ package matchinglogic
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -9,75 +11,110 @@ import (
 )
 
 // MatchesPodDetailed returns whether the pod matches and a list of fields that matched.
-// Note: Namespace and CommonMatch.Labels are pre-filtered by FilterPodList, so we only check items
-// that require in-memory inspection (name patterns, annotations, pod-specific criteria).
-func MatchesPodDetailed(mc *autolabellerv1alpha1.MatchCriteria, pod *corev1.Pod) (bool, []string) {
+func MatchesPodDetailed(mc *autolabellerv1alpha1.MatchCriteria, pod *corev1.Pod, env *Environment) (bool, []string) {
 	matchedFields := []string{}
 	if mc == nil {
 		return true, matchedFields
 	}
 
-	if cm := mc.CommonMatch; cm != nil {
-		// Namespace and Labels are already pre-filtered by FilterPodList, skip them here
-		if name := cm.Name; name != "" {
-			if pod.Name != name {
-				return false, matchedFields
-			}
-			matchedFields = append(matchedFields, "commonMatch.name")
+	ok, commonFields := matchShared(mc, pod, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &pod.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
 		}
-		for k, v := range cm.Annotations {
-			if pod.Annotations[k] != v {
+		if pm.Status != nil {
+			ok, statusFields := matchPodStatus(pm.Status, pod, time.Now())
+			matchedFields = append(matchedFields, statusFields...)
+			if !ok {
 				return false, matchedFields
 			}
-			matchedFields = append(matchedFields, fmt.Sprintf("commonMatch.annotations[%s]", k))
 		}
 	}
 
-	if pm := mc.PodMatch; pm != nil {
-		if pm.HostNetwork != nil {
-			if pod.Spec.HostNetwork != *pm.HostNetwork {
-				return false, matchedFields
-			}
-			matchedFields = append(matchedFields, "podMatch.hostNetwork")
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesPodDetailed(sub, pod, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
+
+// matchPodSpec evaluates PodMatch criteria against a pod spec. It is shared by Pods and by workloads, which are
+// matched through their pod template.
+func matchPodSpec(pm *autolabellerv1alpha1.PodMatchCriteria, spec *corev1.PodSpec) (bool, []string) {
+	matchedFields := []string{}
+	if pm.HostNetwork != nil {
+		if spec.HostNetwork != *pm.HostNetwork {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "podMatch.hostNetwork")
+	}
+	if pm.ServiceAccount != "" {
+		if spec.ServiceAccountName != pm.ServiceAccount {
+			return false, matchedFields
 		}
-		if pm.ServiceAccount != "" {
-			if pod.Spec.ServiceAccountName != pm.ServiceAccount {
+		matchedFields = append(matchedFields, "podMatch.serviceAccount")
+	}
+	if len(pm.NodeSelector) > 0 {
+		for k, v := range pm.NodeSelector {
+			if spec.NodeSelector[k] != v {
 				return false, matchedFields
 			}
-			matchedFields = append(matchedFields, "podMatch.serviceAccount")
+			matchedFields = append(matchedFields, fmt.Sprintf("podMatch.nodeSelector[%s]", k))
 		}
-		if len(pm.NodeSelector) > 0 {
-			for k, v := range pm.NodeSelector {
-				if pod.Spec.NodeSelector[k] != v {
-					return false, matchedFields
-				}
-				matchedFields = append(matchedFields, fmt.Sprintf("podMatch.nodeSelector[%s]", k))
-			}
+	}
+	if pm.RestartPolicy != "" {
+		if string(spec.RestartPolicy) != pm.RestartPolicy {
+			return false, matchedFields
 		}
-		if pm.RestartPolicy != "" {
-			if string(pod.Spec.RestartPolicy) != pm.RestartPolicy {
-				return false, matchedFields
+		matchedFields = append(matchedFields, "podMatch.restartPolicy")
+	}
+	if len(pm.Images) > 0 {
+		matchedAny := ""
+		images := podImages(spec)
+		for _, want := range pm.Images {
+			if slices.ContainsFunc(images, func(image string) bool { return MatchImage(want, image) }) {
+				matchedAny = want
+				break
 			}
-			matchedFields = append(matchedFields, "podMatch.restartPolicy")
 		}
-		if len(pm.Images) > 0 {
-			images := map[string]struct{}{}
-			for _, c := range pod.Spec.Containers {
-				images[c.Image] = struct{}{}
-			}
-			matchedAny := ""
-			for _, want := range pm.Images {
-				if _, ok := images[want]; ok {
-					matchedAny = want
-					break
-				}
-			}
-			if matchedAny == "" {
-				return false, matchedFields
-			}
-			matchedFields = append(matchedFields, fmt.Sprintf("podMatch.images:%s", matchedAny))
+		if matchedAny == "" {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("podMatch.images:%s", matchedAny))
+	}
+	if pm.Security != nil {
+		ok, securityFields := matchPodSecurity(pm.Security, spec)
+		matchedFields = append(matchedFields, securityFields...)
+		if !ok {
+			return false, matchedFields
 		}
 	}
+	ok, resourceFields := matchPodResources(pm, spec)
+	return ok, append(matchedFields, resourceFields...)
+}
 
-	return true, matchedFields
+// podImages returns the images of the pod spec's regular, init and ephemeral containers.
+func podImages(spec *corev1.PodSpec) []string {
+	images := make([]string, 0, len(spec.Containers)+len(spec.InitContainers)+len(spec.EphemeralContainers))
+	for _, c := range spec.Containers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.EphemeralContainers {
+		images = append(images, c.Image)
+	}
+	return images
 }
@@ -0,0 +1,218 @@
+package matchinglogic
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// Container semantics for PodSecurityCriteria.Containers
+const (
+	ContainersAny = "Any"
+	ContainersAll = "All"
+)
+
+// ProfileTypeUnset stands for containers without a seccomp or AppArmor profile in PodSecurityCriteria.
+const ProfileTypeUnset = "Unset"
+
+// containerSecurity is the effective security context of one container, with the pod's settings filled in where
+// the container leaves them unset.
+type containerSecurity struct {
+	sc                 *corev1.SecurityContext
+	runAsNonRoot       *bool
+	runAsUser          *int64
+	seccompProfileType string
+	appArmorProfile    string
+}
+
+// podContainerSecurity returns the effective security context of every regular, init and ephemeral container.
+func podContainerSecurity(spec *corev1.PodSpec) []containerSecurity {
+	contexts := make([]*corev1.SecurityContext, 0, len(spec.Containers)+len(spec.InitContainers)+len(spec.EphemeralContainers))
+	for _, c := range spec.Containers {
+		contexts = append(contexts, c.SecurityContext)
+	}
+	for _, c := range spec.InitContainers {
+		contexts = append(contexts, c.SecurityContext)
+	}
+	for _, c := range spec.EphemeralContainers {
+		contexts = append(contexts, c.SecurityContext)
+	}
+
+	psc := spec.SecurityContext
+	if psc == nil {
+		psc = &corev1.PodSecurityContext{}
+	}
+	result := make([]containerSecurity, 0, len(contexts))
+	for _, sc := range contexts {
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		cs := containerSecurity{
+			sc:                 sc,
+			runAsNonRoot:       containerOr(sc.RunAsNonRoot, psc.RunAsNonRoot),
+			runAsUser:          containerOr(sc.RunAsUser, psc.RunAsUser),
+			seccompProfileType: ProfileTypeUnset,
+			appArmorProfile:    ProfileTypeUnset,
+		}
+		if p := containerOr(sc.SeccompProfile, psc.SeccompProfile); p != nil {
+			cs.seccompProfileType = string(p.Type)
+		}
+		if p := containerOr(sc.AppArmorProfile, psc.AppArmorProfile); p != nil {
+			cs.appArmorProfile = string(p.Type)
+		}
+		result = append(result, cs)
+	}
+	return result
+}
+
+// containerOr returns the container setting if set and the pod setting otherwise.
+func containerOr[T any](container, pod *T) *T {
+	if container != nil {
+		return container
+	}
+	return pod
+}
+
+// matchPodSecurity evaluates PodSecurity criteria against a pod spec.
+func matchPodSecurity(sec *autolabellerv1alpha1.PodSecurityCriteria, spec *corev1.PodSpec) (bool, []string) {
+	matchedFields := []string{}
+	podChecks := []struct {
+		name string
+		want *bool
+		got  bool
+	}{
+		{"hostPID", sec.HostPID, spec.HostPID},
+		{"hostIPC", sec.HostIPC, spec.HostIPC},
+		{"hostPath", sec.HostPath, slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.HostPath != nil })},
+	}
+	for _, check := range podChecks {
+		if check.want == nil {
+			continue
+		}
+		if check.got != *check.want {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "podMatch.security."+check.name)
+	}
+	if len(sec.HostPathPrefixes) > 0 {
+		if !slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool {
+			return v.HostPath != nil && slices.ContainsFunc(sec.HostPathPrefixes, func(prefix string) bool {
+				return hasPathPrefix(v.HostPath.Path, prefix)
+			})
+		}) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "podMatch.security.hostPathPrefixes")
+	}
+
+	containerChecks := []struct {
+		name string
+		set  bool
+		test func(cs containerSecurity) bool
+	}{
+		{"privileged", sec.Privileged != nil, func(cs containerSecurity) bool {
+			return ptr.Deref(cs.sc.Privileged, false) == *sec.Privileged
+		}},
+		{"addedCapabilities", len(sec.AddedCapabilities) > 0, func(cs containerSecurity) bool {
+			return addsCapability(cs.sc.Capabilities, sec.AddedCapabilities)
+		}},
+		{"allowPrivilegeEscalation", sec.AllowPrivilegeEscalation != nil, func(cs containerSecurity) bool {
+			return ptr.Deref(cs.sc.AllowPrivilegeEscalation, true) == *sec.AllowPrivilegeEscalation
+		}},
+		{"runAsNonRoot", sec.RunAsNonRoot != nil, func(cs containerSecurity) bool {
+			return ptr.Deref(cs.runAsNonRoot, false) == *sec.RunAsNonRoot
+		}},
+		{"runAsRoot", sec.RunAsRoot != nil, func(cs containerSecurity) bool {
+			return (cs.runAsUser != nil && *cs.runAsUser == 0) == *sec.RunAsRoot
+		}},
+		{"readOnlyRootFilesystem", sec.ReadOnlyRootFilesystem != nil, func(cs containerSecurity) bool {
+			return ptr.Deref(cs.sc.ReadOnlyRootFilesystem, false) == *sec.ReadOnlyRootFilesystem
+		}},
+		{"seccompProfileTypes", len(sec.SeccompProfileTypes) > 0, func(cs containerSecurity) bool {
+			return slices.Contains(sec.SeccompProfileTypes, cs.seccompProfileType)
+		}},
+		{"appArmorProfileTypes", len(sec.AppArmorProfileTypes) > 0, func(cs containerSecurity) bool {
+			return slices.Contains(sec.AppArmorProfileTypes, cs.appArmorProfile)
+		}},
+	}
+	var containers []containerSecurity
+	for _, check := range containerChecks {
+		if !check.set {
+			continue
+		}
+		if containers == nil {
+			containers = podContainerSecurity(spec)
+		}
+		ok := slices.ContainsFunc(containers, check.test)
+		if sec.Containers == ContainersAll {
+			// A pod without containers has nothing to satisfy the criterion, so it does not match
+			ok = len(containers) > 0 && !slices.ContainsFunc(containers, func(cs containerSecurity) bool { return !check.test(cs) })
+		}
+		if !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "podMatch.security."+check.name)
+	}
+	return true, matchedFields
+}
+
+// normalizeCapability strips the optional CAP_ prefix and upper-cases a capability name.
+func normalizeCapability(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	return strings.TrimPrefix(name, "CAP_")
+}
+
+// addsCapability reports whether caps adds any of wanted, or ALL.
+func addsCapability(caps *corev1.Capabilities, wanted []string) bool {
+	if caps == nil {
+		return false
+	}
+	return slices.ContainsFunc(caps.Add, func(added corev1.Capability) bool {
+		name := normalizeCapability(string(added))
+		return name == "ALL" || slices.ContainsFunc(wanted, func(w string) bool { return normalizeCapability(w) == name })
+	})
+}
+
+// hasPathPrefix reports whether p is prefix or lies below it, comparing whole path elements.
+func hasPathPrefix(p, prefix string) bool {
+	p, prefix = path.Clean(p), path.Clean(prefix)
+	return prefix == "/" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+func validatePodSecurity(sec *autolabellerv1alpha1.PodSecurityCriteria, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch sec.Containers {
+	case "", ContainersAny, ContainersAll:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("containers"), sec.Containers, []string{ContainersAny, ContainersAll}))
+	}
+	for i, c := range sec.AddedCapabilities {
+		if normalizeCapability(c) == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("addedCapabilities").Index(i), c, "must be a capability name"))
+		}
+	}
+	for i, prefix := range sec.HostPathPrefixes {
+		if !path.IsAbs(prefix) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hostPathPrefixes").Index(i), prefix, "must be an absolute path"))
+		}
+	}
+	profileTypes := []string{string(corev1.SeccompProfileTypeUnconfined), string(corev1.SeccompProfileTypeRuntimeDefault),
+		string(corev1.SeccompProfileTypeLocalhost), ProfileTypeUnset}
+	for i, t := range sec.SeccompProfileTypes {
+		if !slices.Contains(profileTypes, t) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("seccompProfileTypes").Index(i), t, profileTypes))
+		}
+	}
+	for i, t := range sec.AppArmorProfileTypes {
+		if !slices.Contains(profileTypes, t) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("appArmorProfileTypes").Index(i), t, profileTypes))
+		}
+	}
+	return allErrs
+}
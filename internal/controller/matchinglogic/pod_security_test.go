@@ -0,0 +1,112 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Pod security criteria", func() {
+	var pod *corev1.Pod
+	securing := func(sec autolabellerv1alpha1.PodSecurityCriteria) *autolabellerv1alpha1.MatchCriteria {
+		return &autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{Security: &sec}}
+	}
+	matches := func(sec autolabellerv1alpha1.PodSecurityCriteria) bool {
+		ok, _ := MatchesPodDetailed(securing(sec), pod, nil)
+		return ok
+	}
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "monitoring"},
+			Spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot:   ptr.To(true),
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				},
+				InitContainers: []corev1.Container{{Name: "setup", SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.To(true),
+					RunAsUser:  ptr.To(int64(0)),
+				}}},
+				Containers: []corev1.Container{{Name: "agent", SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: ptr.To(false),
+					ReadOnlyRootFilesystem:   ptr.To(true),
+					Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+				}}},
+				HostPID: true,
+				Volumes: []corev1.Volume{{Name: "docker", VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"},
+				}}},
+			},
+		}
+	})
+
+	It("Should match container criteria against any container, including init containers", func() {
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{Privileged: ptr.To(true)})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{RunAsRoot: ptr.To(true)})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{AddedCapabilities: []string{"cap_net_admin"}})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{AddedCapabilities: []string{"SYS_ADMIN"}})).To(BeFalse())
+	})
+
+	It("Should require every container to match under All", func() {
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{Containers: ContainersAll, ReadOnlyRootFilesystem: ptr.To(true)})).To(BeFalse())
+		// The pod-level runAsNonRoot and seccomp profile apply to both containers
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{
+			Containers:          ContainersAll,
+			RunAsNonRoot:        ptr.To(true),
+			SeccompProfileTypes: []string{"RuntimeDefault"},
+		})).To(BeTrue())
+		// Unset allowPrivilegeEscalation counts as allowed
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{Containers: ContainersAll, AllowPrivilegeEscalation: ptr.To(false)})).To(BeFalse())
+	})
+
+	It("Should not match All on a pod without containers", func() {
+		pod.Spec = corev1.PodSpec{SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true)}}
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{Containers: ContainersAll, RunAsNonRoot: ptr.To(true)})).To(BeFalse())
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{Containers: ContainersAll, Privileged: ptr.To(false)})).To(BeFalse())
+	})
+
+	It("Should include ephemeral containers", func() {
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{AppArmorProfileTypes: []string{"Unconfined"}})).To(BeFalse())
+		pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:            "debug",
+			SecurityContext: &corev1.SecurityContext{AppArmorProfile: &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeUnconfined}},
+		}}}
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{AppArmorProfileTypes: []string{"Unconfined"}})).To(BeTrue())
+	})
+
+	It("Should match host namespaces and hostPath prefixes", func() {
+		ok, fields := MatchesPodDetailed(securing(autolabellerv1alpha1.PodSecurityCriteria{
+			HostPID:          ptr.To(true),
+			HostIPC:          ptr.To(false),
+			HostPathPrefixes: []string{"/var/run"},
+		}), pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ConsistOf("podMatch.security.hostPID", "podMatch.security.hostIPC", "podMatch.security.hostPathPrefixes"))
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{HostPathPrefixes: []string{"/var/ru"}})).To(BeFalse())
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{HostPathPrefixes: []string{"/"}})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodSecurityCriteria{HostPath: ptr.To(false)})).To(BeFalse())
+	})
+
+	It("Should reject invalid criteria", func() {
+		errs := ValidateMatchCriteria("", "Deployment", securing(autolabellerv1alpha1.PodSecurityCriteria{
+			Containers:          "Some",
+			HostPathPrefixes:    []string{"var/run"},
+			SeccompProfileTypes: []string{"Default"},
+		}), field.NewPath("match"))
+		var paths []string
+		for _, err := range errs {
+			paths = append(paths, err.Field)
+		}
+		Expect(paths).To(ConsistOf(
+			"match.podMatch.security.containers",
+			"match.podMatch.security.hostPathPrefixes[0]",
+			"match.podMatch.security.seccompProfileTypes[0]",
+		))
+	})
+})
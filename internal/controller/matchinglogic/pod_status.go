@@ -0,0 +1,118 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// NeedsPodStatus reports whether mc or any criteria nested in it match the pod's runtime status. Such criteria
+// cannot be evaluated at admission, before the pod has a status.
+func NeedsPodStatus(mc *autolabellerv1alpha1.MatchCriteria) bool {
+	return anyCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) bool {
+		return c.PodMatch != nil && c.PodMatch.Status != nil
+	})
+}
+
+// matchPodStatus evaluates PodStatus criteria against a pod.
+func matchPodStatus(st *autolabellerv1alpha1.PodStatusCriteria, pod *corev1.Pod, now time.Time) (bool, []string) {
+	matchedFields := []string{}
+	if len(st.Phases) > 0 {
+		if !slices.Contains(st.Phases, string(pod.Status.Phase)) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("podMatch.status.phases:%s", pod.Status.Phase))
+	}
+	if len(st.QOSClasses) > 0 {
+		if !slices.Contains(st.QOSClasses, string(pod.Status.QOSClass)) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("podMatch.status.qosClasses:%s", pod.Status.QOSClass))
+	}
+	statuses := podContainerStatuses(pod)
+	if st.RestartCount != "" {
+		restarts := int32(0)
+		for _, cs := range statuses {
+			restarts = max(restarts, cs.RestartCount)
+		}
+		if ok, err := MatchInt(st.RestartCount, int64(restarts)); err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("podMatch.status.restartCount:%d", restarts))
+	}
+	if len(st.Reasons) > 0 {
+		reason := ""
+		for _, cs := range statuses {
+			reasons := containerReasons(cs)
+			if i := slices.IndexFunc(reasons, func(r string) bool { return slices.Contains(st.Reasons, r) }); i >= 0 {
+				reason = reasons[i]
+				break
+			}
+		}
+		if reason == "" {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("podMatch.status.reasons:%s", reason))
+	}
+	if st.Ready != nil {
+		ready := slices.ContainsFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+			return c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue
+		})
+		if ready != *st.Ready {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, "podMatch.status.ready")
+	}
+	if st.PendingFor != "" {
+		if pod.Status.Phase != corev1.PodPending {
+			return false, matchedFields
+		}
+		pending := now.Sub(pod.CreationTimestamp.Time)
+		if ok, err := MatchDuration(st.PendingFor, pending); err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("podMatch.status.pendingFor:%s", pending.Truncate(time.Second)))
+	}
+	return true, matchedFields
+}
+
+// podContainerStatuses returns the statuses of the pod's regular, init and ephemeral containers.
+func podContainerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	return slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses, pod.Status.EphemeralContainerStatuses)
+}
+
+// containerReasons returns the reasons reported for a container's current and last state.
+func containerReasons(cs corev1.ContainerStatus) []string {
+	var reasons []string
+	if w := cs.State.Waiting; w != nil && w.Reason != "" {
+		reasons = append(reasons, w.Reason)
+	}
+	if t := cs.State.Terminated; t != nil && t.Reason != "" {
+		reasons = append(reasons, t.Reason)
+	}
+	if t := cs.LastTerminationState.Terminated; t != nil && t.Reason != "" {
+		reasons = append(reasons, t.Reason)
+	}
+	return reasons
+}
+
+// PodRecheckAfter returns how long until a pendingFor criterion in mc may change its outcome for pod while it stays
+// Pending, so the rule can be re-evaluated without a pod event. It returns 0 when no such change is due.
+func PodRecheckAfter(mc *autolabellerv1alpha1.MatchCriteria, pod *corev1.Pod, now time.Time) time.Duration {
+	if pod.Status.Phase != corev1.PodPending {
+		return 0
+	}
+	pending := now.Sub(pod.CreationTimestamp.Time)
+	var next time.Duration
+	forEachCriteria(mc, func(c *autolabellerv1alpha1.MatchCriteria) {
+		if c.PodMatch == nil || c.PodMatch.Status == nil || c.PodMatch.Status.PendingFor == "" {
+			return
+		}
+		next = Sooner(next, durationRecheckAfter(c.PodMatch.Status.PendingFor, pending))
+	})
+	return next
+}
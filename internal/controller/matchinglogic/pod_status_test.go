@@ -0,0 +1,105 @@
+package matchinglogic
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Pod status criteria", func() {
+	var pod *corev1.Pod
+	withStatus := func(st autolabellerv1alpha1.PodStatusCriteria) *autolabellerv1alpha1.MatchCriteria {
+		return &autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{Status: &st}}
+	}
+	matches := func(st autolabellerv1alpha1.PodStatusCriteria) bool {
+		ok, _ := MatchesPodDetailed(withStatus(st), pod, nil)
+		return ok
+	}
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "jobs", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSBurstable,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+				},
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "migrate", RestartCount: 1}},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:                 "worker",
+					RestartCount:         7,
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+				}},
+			},
+		}
+	})
+
+	It("Should match phase, QoS class and readiness", func() {
+		ok, fields := MatchesPodDetailed(withStatus(autolabellerv1alpha1.PodStatusCriteria{
+			Phases:     []string{"Pending", "Running"},
+			QOSClasses: []string{"Burstable"},
+			Ready:      ptr.To(false),
+		}), pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ConsistOf("podMatch.status.phases:Running", "podMatch.status.qosClasses:Burstable", "podMatch.status.ready"))
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{Phases: []string{"Succeeded"}})).To(BeFalse())
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{Ready: ptr.To(true)})).To(BeFalse())
+	})
+
+	It("Should compare the highest restart count", func() {
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{RestartCount: ">5"})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{RestartCount: "<=1"})).To(BeFalse())
+	})
+
+	It("Should match current and last termination reasons", func() {
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{Reasons: []string{"CrashLoopBackOff"}})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{Reasons: []string{"OOMKilled"}})).To(BeTrue())
+		Expect(matches(autolabellerv1alpha1.PodStatusCriteria{Reasons: []string{"ImagePullBackOff"}})).To(BeFalse())
+	})
+
+	It("Should stop matching once the state clears", func() {
+		mc := withStatus(autolabellerv1alpha1.PodStatusCriteria{Reasons: []string{"CrashLoopBackOff"}})
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+		pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{}
+		ok, _ := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should match pods pending longer than a threshold and schedule a recheck before that", func() {
+		mc := withStatus(autolabellerv1alpha1.PodStatusCriteria{PendingFor: ">10m"})
+		Expect(NeedsPodStatus(&autolabellerv1alpha1.MatchCriteria{AnyOf: []autolabellerv1alpha1.MatchCriteria{*mc}})).To(BeTrue())
+		ok, _ := MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse(), "running pods are not pending")
+		Expect(PodRecheckAfter(mc, pod, time.Now())).To(BeZero())
+
+		pod.Status.Phase = corev1.PodPending
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeTrue())
+		Expect(PodRecheckAfter(mc, pod, time.Now())).To(BeZero(), "the threshold has already passed")
+
+		now := time.Now()
+		pod.CreationTimestamp = metav1.NewTime(now.Add(-4 * time.Minute))
+		ok, _ = MatchesPodDetailed(mc, pod, nil)
+		Expect(ok).To(BeFalse())
+		Expect(PodRecheckAfter(mc, pod, now)).To(Equal(6*time.Minute + time.Second))
+	})
+
+	It("Should only be available for Pod targets", func() {
+		mc := withStatus(autolabellerv1alpha1.PodStatusCriteria{RestartCount: "many", PendingFor: ">soon"})
+		errs := ValidateMatchCriteria("", "Deployment", mc, field.NewPath("match"))
+		var paths []string
+		for _, err := range errs {
+			paths = append(paths, err.Field)
+		}
+		Expect(paths).To(ConsistOf("match.podMatch.status", "match.podMatch.status.restartCount", "match.podMatch.status.pendingFor"))
+	})
+})
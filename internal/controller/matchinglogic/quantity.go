@@ -0,0 +1,138 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// Missing resource policies for PodMatchCriteria.MissingResourcePolicy
+const (
+	MissingResourceZero    = "Zero"
+	MissingResourceNoMatch = "NoMatch"
+)
+
+// MatchQuantity reports whether actual satisfies the comparison expression, e.g. ">1", "<=512Mi" or "1..2".
+func MatchQuantity(expr string, actual resource.Quantity) (bool, error) {
+	cmp, err := parseQuantityExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	return cmp.evaluate(func(operand string) (int, error) {
+		q, err := resource.ParseQuantity(operand)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid quantity: %w", operand, err)
+		}
+		return actual.Cmp(q), nil
+	})
+}
+
+// parseQuantityExpression parses expr and checks that every operand is a quantity.
+func parseQuantityExpression(expr string) (Comparison, error) {
+	cmp, err := ParseComparison(expr)
+	if err != nil {
+		return Comparison{}, err
+	}
+	for _, operand := range []string{cmp.Value, cmp.Upper} {
+		if operand == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(operand); err != nil {
+			return Comparison{}, fmt.Errorf("%q is not a valid quantity: %w", operand, err)
+		}
+	}
+	if err := cmp.checkRange(func(low, high string) int {
+		l, h := resource.MustParse(low), resource.MustParse(high)
+		return l.Cmp(h)
+	}); err != nil {
+		return Comparison{}, err
+	}
+	return cmp, nil
+}
+
+// podResourceTotal sums the request (or limit) for name across the pod spec's containers. With includeInit the result
+// is the larger of that sum and the largest init container, matching how the scheduler sizes a pod.
+// missing reports whether any considered container does not set the resource.
+func podResourceTotal(spec *corev1.PodSpec, name corev1.ResourceName, limits, includeInit bool) (total resource.Quantity, missing bool) {
+	get := func(c corev1.Container) (resource.Quantity, bool) {
+		list := c.Resources.Requests
+		if limits {
+			list = c.Resources.Limits
+		}
+		q, ok := list[name]
+		return q, ok
+	}
+
+	for _, c := range spec.Containers {
+		q, ok := get(c)
+		if !ok {
+			missing = true
+			continue
+		}
+		total.Add(q)
+	}
+	if includeInit {
+		for _, c := range spec.InitContainers {
+			q, ok := get(c)
+			if !ok {
+				missing = true
+				continue
+			}
+			if q.Cmp(total) > 0 {
+				total = q.DeepCopy()
+			}
+		}
+	}
+	return total, missing
+}
+
+// matchPodResources evaluates the request/limit expressions of pm against the pod spec. Malformed expressions never match;
+// the validating webhook rejects them before they reach the controller.
+func matchPodResources(pm *autolabellerv1alpha1.PodMatchCriteria, spec *corev1.PodSpec) (bool, []string) {
+	matchedFields := []string{}
+	criteria := []struct {
+		field, expr string
+		name        corev1.ResourceName
+		limits      bool
+	}{
+		{"podMatch.cpuRequests", pm.CPURequests, corev1.ResourceCPU, false},
+		{"podMatch.memoryRequests", pm.MemoryRequests, corev1.ResourceMemory, false},
+		{"podMatch.cpuLimits", pm.CPULimits, corev1.ResourceCPU, true},
+		{"podMatch.memoryLimits", pm.MemoryLimits, corev1.ResourceMemory, true},
+	}
+	for _, c := range criteria {
+		if c.expr == "" {
+			continue
+		}
+		total, missing := podResourceTotal(spec, c.name, c.limits, pm.IncludeInitContainers)
+		if missing && pm.MissingResourcePolicy == MissingResourceNoMatch {
+			return false, matchedFields
+		}
+		ok, err := MatchQuantity(c.expr, total)
+		if err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("%s:%s", c.field, total.String()))
+	}
+	return true, matchedFields
+}
+
+// matchNodeResources evaluates nodeMatch capacity or allocatable expressions against the node's resource list.
+// Resources the node does not report count as 0.
+func matchNodeResources(fieldName string, exprs map[string]string, resources corev1.ResourceList) (bool, []string) {
+	matchedFields := []string{}
+	for _, name := range slices.Sorted(maps.Keys(exprs)) {
+		actual := resources[corev1.ResourceName(name)]
+		ok, err := MatchQuantity(exprs[name], actual)
+		if err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("nodeMatch.%s[%s]:%s", fieldName, name, actual.String()))
+	}
+	return true, matchedFields
+}
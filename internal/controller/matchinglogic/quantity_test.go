@@ -0,0 +1,151 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Quantity comparisons", func() {
+	DescribeTable("MatchQuantity",
+		func(expr, actual string, expected bool) {
+			ok, err := MatchQuantity(expr, resource.MustParse(actual))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("greater than", ">1", "1500m", true),
+		Entry("greater than equal value", ">1", "1000m", false),
+		Entry("less or equal across units", "<=512Mi", "0.5Gi", true),
+		Entry("equal", "==256Mi", "268435456", true),
+		Entry("bare value is equality", "2", "2000m", true),
+		Entry("not equal", "!=1", "2", true),
+		Entry("greater or equal", ">= 500m", "0.5", true),
+		Entry("less than", "<100m", "100m", false),
+		Entry("range inclusive lower", "250m..2", "250m", true),
+		Entry("range inclusive upper", "250m..2", "2", true),
+		Entry("range outside", "250m..2", "3", false),
+	)
+
+	It("Should report malformed operands", func() {
+		_, err := MatchQuantity(">lots", resource.MustParse("1"))
+		Expect(err).To(HaveOccurred())
+		_, err = MatchQuantity("2Gi..1Gi", resource.MustParse("1536Mi"))
+		Expect(err).To(MatchError(ContainSubstring("lower bound exceeds upper bound")))
+	})
+
+	Describe("Pod resource criteria", func() {
+		container := func(requests, limits corev1.ResourceList) corev1.Container {
+			return corev1.Container{Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits}}
+		}
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			pod = &corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					container(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}, nil),
+				},
+				Containers: []corev1.Container{
+					container(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+						corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}),
+					container(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, nil),
+				},
+			}}
+		})
+
+		match := func(pm *autolabellerv1alpha1.PodMatchCriteria) (bool, []string) {
+			return MatchesPodDetailed(&autolabellerv1alpha1.MatchCriteria{PodMatch: pm}, pod, nil)
+		}
+
+		It("Should sum requests across regular containers", func() {
+			ok, fields := match(&autolabellerv1alpha1.PodMatchCriteria{CPURequests: "==1500m"})
+			Expect(ok).To(BeTrue())
+			Expect(fields).To(ContainElement("podMatch.cpuRequests:1500m"))
+		})
+
+		It("Should use the effective value when init containers are included", func() {
+			ok, _ := match(&autolabellerv1alpha1.PodMatchCriteria{CPURequests: "==3", IncludeInitContainers: true})
+			Expect(ok).To(BeTrue())
+			ok, _ = match(&autolabellerv1alpha1.PodMatchCriteria{CPURequests: "==1500m", IncludeInitContainers: true})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Should count missing resources as zero by default", func() {
+			ok, fields := match(&autolabellerv1alpha1.PodMatchCriteria{MemoryLimits: "<1Gi"})
+			Expect(ok).To(BeTrue())
+			Expect(fields).To(ContainElement("podMatch.memoryLimits:512Mi"))
+		})
+
+		It("Should not match when a container is missing the resource and NoMatch is set", func() {
+			ok, _ := match(&autolabellerv1alpha1.PodMatchCriteria{MemoryLimits: "<1Gi", MissingResourcePolicy: MissingResourceNoMatch})
+			Expect(ok).To(BeFalse())
+			ok, _ = match(&autolabellerv1alpha1.PodMatchCriteria{CPURequests: ">1", MissingResourcePolicy: MissingResourceNoMatch})
+			Expect(ok).To(BeTrue())
+		})
+
+		It("Should require every resource criterion to match", func() {
+			ok, _ := match(&autolabellerv1alpha1.PodMatchCriteria{CPURequests: ">1", MemoryRequests: ">1Gi"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Node resource criteria", func() {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-node"},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("16"),
+					corev1.ResourceMemory: resource.MustParse("64Gi"),
+					"nvidia.com/gpu":      resource.MustParse("4"),
+				},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("15500m"),
+					corev1.ResourceMemory: resource.MustParse("60Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			},
+		}
+		match := func(nm *autolabellerv1alpha1.NodeMatchCriteria) (bool, []string) {
+			return MatchesNodeDetailed(&autolabellerv1alpha1.MatchCriteria{NodeMatch: nm}, node, nil)
+		}
+
+		It("Should compare capacity including extended resources", func() {
+			ok, fields := match(&autolabellerv1alpha1.NodeMatchCriteria{Capacity: map[string]string{
+				"cpu": ">=16", "memory": "32Gi..128Gi", "nvidia.com/gpu": ">0",
+			}})
+			Expect(ok).To(BeTrue())
+			Expect(fields).To(ContainElement("nodeMatch.capacity[nvidia.com/gpu]:4"))
+		})
+
+		It("Should compare allocatable resources", func() {
+			ok, _ := match(&autolabellerv1alpha1.NodeMatchCriteria{Allocatable: map[string]string{"cpu": ">=16"}})
+			Expect(ok).To(BeFalse())
+			ok, _ = match(&autolabellerv1alpha1.NodeMatchCriteria{Allocatable: map[string]string{"pods": ">100"}})
+			Expect(ok).To(BeTrue())
+		})
+
+		It("Should count resources the node does not report as zero", func() {
+			ok, _ := match(&autolabellerv1alpha1.NodeMatchCriteria{Capacity: map[string]string{"hugepages-2Mi": "==0"}})
+			Expect(ok).To(BeTrue())
+			ok, _ = match(&autolabellerv1alpha1.NodeMatchCriteria{Allocatable: map[string]string{"nvidia.com/gpu": ">0"}})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Should reject invalid resource names and expressions", func() {
+			errs := ValidateMatchCriteria("", "Node", &autolabellerv1alpha1.MatchCriteria{NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{
+				Capacity:    map[string]string{"cpu": ">lots", "bad name": ">1"},
+				Allocatable: map[string]string{"memory": ""},
+			}}, field.NewPath("match"))
+			var paths []string
+			for _, err := range errs {
+				paths = append(paths, err.Field)
+			}
+			Expect(paths).To(ConsistOf("match.nodeMatch.capacity[bad name]", "match.nodeMatch.capacity[cpu]",
+				"match.nodeMatch.allocatable[memory]"))
+		})
+	})
+})
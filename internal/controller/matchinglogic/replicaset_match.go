@@ -0,0 +1,73 @@
+package matchinglogic
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesReplicaSetDetailed returns whether the ReplicaSet matches and a list of fields that matched.
+// PodMatch criteria are evaluated against the pod template.
+func MatchesReplicaSetDetailed(mc *autolabellerv1alpha1.MatchCriteria, rs *appsv1.ReplicaSet, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, rs, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if rm := mc.ReplicaSetMatch; rm != nil {
+		if rm.Replicas != "" {
+			ok, desired := matchReplicas(rm.Replicas, rs.Spec.Replicas)
+			if !ok {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("replicaSetMatch.replicas:%d", desired))
+		}
+		// Ownership is judged by the controller owner reference, as set by Deployments
+		owner := metav1.GetControllerOf(rs)
+		if rm.HasOwner != nil {
+			if (owner != nil) != *rm.HasOwner {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "replicaSetMatch.hasOwner")
+		}
+		if rm.OwnerKind != "" {
+			if owner == nil || owner.Kind != rm.OwnerKind {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "replicaSetMatch.ownerKind")
+		}
+		if rm.OwnerName != "" {
+			if owner == nil || !MatchGlob(rm.OwnerName, owner.Name) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "replicaSetMatch.ownerName")
+		}
+	}
+
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &rs.Spec.Template.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesReplicaSetDetailed(sub, rs, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
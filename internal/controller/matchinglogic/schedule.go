@@ -0,0 +1,66 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser accepts the schedule syntax of batch/v1 CronJobs: five fields plus descriptors such as @hourly.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+const (
+	// scheduleSampleRuns bounds how many consecutive runs are inspected to find the shortest interval.
+	scheduleSampleRuns = 1024
+	// scheduleSampleWindow is long enough to see two runs of any valid schedule, including "0 0 29 2 *".
+	scheduleSampleWindow = 9 * 366 * 24 * time.Hour
+)
+
+// scheduleEpoch is a fixed reference time so the computed interval does not depend on when a rule is evaluated.
+var scheduleEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// scheduleIntervals caches the intervals of CronJob schedules, which are shared by many CronJobs and evaluated on
+// every reconcile.
+var scheduleIntervals = newCompileCache(1024, computeScheduleInterval)
+
+// ScheduleInterval returns the shortest interval between two consecutive runs of a cron schedule.
+// Time zone prefixes (CRON_TZ=, TZ=) are ignored and runs are computed in UTC.
+func ScheduleInterval(schedule string) (time.Duration, error) {
+	return scheduleIntervals.get(schedule)
+}
+
+func computeScheduleInterval(schedule string) (time.Duration, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=")) {
+		fields = fields[1:]
+	}
+	sched, err := cronParser.Parse(strings.Join(fields, " "))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+
+	var shortest time.Duration
+	prev := sched.Next(scheduleEpoch)
+	end := scheduleEpoch.Add(scheduleSampleWindow)
+	for range scheduleSampleRuns {
+		next := sched.Next(prev)
+		if next.IsZero() || next.After(end) {
+			break
+		}
+		if gap := next.Sub(prev); shortest == 0 || gap < shortest {
+			shortest = gap
+		}
+		prev = next
+	}
+	if shortest == 0 {
+		return 0, fmt.Errorf("schedule %q does not run repeatedly", schedule)
+	}
+	return shortest, nil
+}
+
+// normalizeSchedule collapses whitespace so equivalent spellings of a schedule compare equal.
+func normalizeSchedule(schedule string) string {
+	return strings.Join(strings.Fields(schedule), " ")
+}
@@ -0,0 +1,107 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesServiceDetailed returns whether the Service matches and a list of fields that matched.
+func MatchesServiceDetailed(mc *autolabellerv1alpha1.MatchCriteria, svc *corev1.Service, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, svc, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if sm := mc.ServiceMatch; sm != nil {
+		if sm.Type != "" {
+			// Services without an explicit type are ClusterIP
+			svcType := svc.Spec.Type
+			if svcType == "" {
+				svcType = corev1.ServiceTypeClusterIP
+			}
+			if string(svcType) != sm.Type {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "serviceMatch.type")
+		}
+		for _, want := range sm.Ports {
+			port, protocol, err := ParseServicePort(want)
+			if err != nil || !slices.ContainsFunc(svc.Spec.Ports, func(p corev1.ServicePort) bool {
+				return p.Port == port && (protocol == "" || servicePortProtocol(p) == protocol)
+			}) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("serviceMatch.ports:%s", want))
+		}
+		for _, protocol := range sm.Protocols {
+			if !slices.ContainsFunc(svc.Spec.Ports, func(p corev1.ServicePort) bool {
+				return servicePortProtocol(p) == corev1.Protocol(protocol)
+			}) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("serviceMatch.protocols:%s", protocol))
+		}
+		for k, v := range sm.Selector {
+			if val, ok := svc.Spec.Selector[k]; !ok || val != v {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("serviceMatch.selector[%s]", k))
+		}
+		if sm.Headless != nil {
+			if (svc.Spec.ClusterIP == corev1.ClusterIPNone) != *sm.Headless {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "serviceMatch.headless")
+		}
+		if sm.ExternalTrafficPolicy != "" {
+			if string(svc.Spec.ExternalTrafficPolicy) != sm.ExternalTrafficPolicy {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "serviceMatch.externalTrafficPolicy")
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesServiceDetailed(sub, svc, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
+
+// ParseServicePort parses a "port" or "port/protocol" criterion. The protocol is empty when not given.
+func ParseServicePort(s string) (int32, corev1.Protocol, error) {
+	portStr, protocol, _ := strings.Cut(s, "/")
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, "", fmt.Errorf("port %q must be a number between 1 and 65535", portStr)
+	}
+	switch p := corev1.Protocol(protocol); p {
+	case "", corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+		return int32(port), p, nil
+	}
+	return 0, "", fmt.Errorf("protocol %q must be one of TCP, UDP, SCTP", protocol)
+}
+
+// servicePortProtocol returns the port's protocol, which defaults to TCP.
+func servicePortProtocol(p corev1.ServicePort) corev1.Protocol {
+	if p.Protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return p.Protocol
+}
@@ -0,0 +1,76 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Service matching", func() {
+	var svc *corev1.Service
+
+	BeforeEach(func() {
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec: corev1.ServiceSpec{
+				Type:                  corev1.ServiceTypeLoadBalancer,
+				Selector:              map[string]string{"app": "web", "tier": "frontend"},
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				Ports: []corev1.ServicePort{
+					{Name: "https", Port: 443},
+					{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+				},
+			},
+		}
+	})
+
+	match := func(sm *autolabellerv1alpha1.ServiceMatchCriteria) bool {
+		ok, _ := MatchesServiceDetailed(&autolabellerv1alpha1.MatchCriteria{ServiceMatch: sm}, svc, nil)
+		return ok
+	}
+
+	It("Should match LoadBalancer services", func() {
+		ok, fields := MatchesServiceDetailed(&autolabellerv1alpha1.MatchCriteria{
+			ServiceMatch: &autolabellerv1alpha1.ServiceMatchCriteria{Type: "LoadBalancer"},
+		}, svc, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElement("serviceMatch.type"))
+		svc.Spec.Type = ""
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Type: "LoadBalancer"})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Type: "ClusterIP"})).To(BeTrue())
+	})
+
+	It("Should match ports and protocols", func() {
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Ports: []string{"443", "53/UDP"}})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Ports: []string{"443/TCP"}})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Ports: []string{"53/TCP"}})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Ports: []string{"80"}})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Protocols: []string{"TCP", "UDP"}})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Protocols: []string{"SCTP"}})).To(BeFalse())
+	})
+
+	It("Should match selector, headless and traffic policy", func() {
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Selector: map[string]string{"app": "web"}})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Selector: map[string]string{"app": "api"}})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Headless: ptr.To(false)})).To(BeTrue())
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{Headless: ptr.To(true)})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{ExternalTrafficPolicy: "Local"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ServiceMatchCriteria{ExternalTrafficPolicy: "Cluster"})).To(BeFalse())
+	})
+
+	DescribeTable("ParseServicePort rejects malformed ports",
+		func(s string) {
+			_, _, err := ParseServicePort(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("zero", "0"),
+		Entry("too large", "70000"),
+		Entry("name", "https"),
+		Entry("bad protocol", "443/HTTP"),
+	)
+})
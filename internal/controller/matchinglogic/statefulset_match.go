@@ -0,0 +1,86 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// MatchesStatefulSetDetailed returns whether the StatefulSet matches and a list of fields that matched.
+// PodMatch criteria are evaluated against the pod template.
+func MatchesStatefulSetDetailed(mc *autolabellerv1alpha1.MatchCriteria, sts *appsv1.StatefulSet, env *Environment) (bool, []string) {
+	matchedFields := []string{}
+	if mc == nil {
+		return true, matchedFields
+	}
+
+	ok, commonFields := matchShared(mc, sts, false, env)
+	matchedFields = append(matchedFields, commonFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	if sm := mc.StatefulSetMatch; sm != nil {
+		if sm.Replicas != "" {
+			ok, desired := matchReplicas(sm.Replicas, sts.Spec.Replicas)
+			if !ok {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("statefulSetMatch.replicas:%d", desired))
+		}
+		if sm.PodManagementPolicy != "" {
+			// An unset policy defaults to OrderedReady
+			policy := sts.Spec.PodManagementPolicy
+			if policy == "" {
+				policy = appsv1.OrderedReadyPodManagement
+			}
+			if string(policy) != sm.PodManagementPolicy {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "statefulSetMatch.podManagementPolicy")
+		}
+		templates := sts.Spec.VolumeClaimTemplates
+		if sm.HasVolumeClaimTemplates != nil {
+			if (len(templates) > 0) != *sm.HasVolumeClaimTemplates {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "statefulSetMatch.hasVolumeClaimTemplates")
+		}
+		for _, want := range sm.VolumeClaimTemplates {
+			if !slices.ContainsFunc(templates, func(pvc corev1.PersistentVolumeClaim) bool { return MatchGlob(want, pvc.Name) }) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, fmt.Sprintf("statefulSetMatch.volumeClaimTemplates:%s", want))
+		}
+		if sm.StorageClass != "" {
+			if !slices.ContainsFunc(templates, func(pvc corev1.PersistentVolumeClaim) bool {
+				return pvc.Spec.StorageClassName != nil && MatchGlob(sm.StorageClass, *pvc.Spec.StorageClassName)
+			}) {
+				return false, matchedFields
+			}
+			matchedFields = append(matchedFields, "statefulSetMatch.storageClass")
+		}
+	}
+
+	if pm := mc.PodMatch; pm != nil {
+		ok, specFields := matchPodSpec(pm, &sts.Spec.Template.Spec)
+		matchedFields = append(matchedFields, specFields...)
+		if !ok {
+			return false, matchedFields
+		}
+	}
+
+	ok, groupFields := matchGroups(mc, func(sub *autolabellerv1alpha1.MatchCriteria) (bool, []string) {
+		return MatchesStatefulSetDetailed(sub, sts, env)
+	})
+	matchedFields = append(matchedFields, groupFields...)
+	if !ok {
+		return false, matchedFields
+	}
+
+	return true, matchedFields
+}
@@ -0,0 +1,14 @@
+package matchinglogic
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMatchingLogic(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Matching Logic Suite")
+}
@@ -0,0 +1,28 @@
+package matchinglogic
+
+// builtinTargetAPIVersions maps the TargetKinds with typed support to the apiVersion they are served under.
+var builtinTargetAPIVersions = map[string]string{
+	"Pod":         "v1",
+	"Node":        "v1",
+	"Namespace":   "v1",
+	"Service":     "v1",
+	"Deployment":  "apps/v1",
+	"StatefulSet": "apps/v1",
+	"DaemonSet":   "apps/v1",
+	"ReplicaSet":  "apps/v1",
+	"Job":         "batch/v1",
+	"CronJob":     "batch/v1",
+}
+
+// BuiltinTargetAPIVersion returns the apiVersion of a built-in TargetKind, or false if kind has no typed support.
+func BuiltinTargetAPIVersion(kind string) (string, bool) {
+	apiVersion, ok := builtinTargetAPIVersions[kind]
+	return apiVersion, ok
+}
+
+// IsBuiltinTarget reports whether apiVersion/kind is handled by a typed matcher. An empty apiVersion stands for
+// the built-in kind's own apiVersion.
+func IsBuiltinTarget(apiVersion, kind string) bool {
+	builtin, ok := builtinTargetAPIVersions[kind]
+	return ok && (apiVersion == "" || apiVersion == builtin)
+}
@@ -0,0 +1,418 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// PodTemplateKinds are the TargetKinds whose pods can be matched with podMatch criteria: Pods directly and
+// workloads through their pod template.
+var PodTemplateKinds = []string{"Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob"}
+
+// ValidateMatchCriteria checks that mc only uses criteria blocks that fit the target and that every expression,
+// pattern and label in it is well formed. Targets that are not built in only accept the criteria shared by every
+// kind. It returns one error per offending field.
+func ValidateMatchCriteria(targetAPIVersion, targetKind string, mc *autolabellerv1alpha1.MatchCriteria, fldPath *field.Path) field.ErrorList {
+	if !IsBuiltinTarget(targetAPIVersion, targetKind) {
+		targetKind = targetAPIVersion + " " + targetKind
+	}
+	return validateMatchCriteria(targetKind, mc, fldPath, 0)
+}
+
+// MaxMatchNesting is how deep AnyOf, AllOf and Not groups may be nested.
+const MaxMatchNesting = 4
+
+// validateMatchGroups validates the criteria nested in mc's groups at the given nesting depth.
+func validateMatchGroups(targetKind string, mc *autolabellerv1alpha1.MatchCriteria, fldPath *field.Path, depth int) field.ErrorList {
+	var allErrs field.ErrorList
+	if depth >= MaxMatchNesting && (len(mc.AnyOf) > 0 || len(mc.AllOf) > 0 || mc.Not != nil) {
+		return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("anyOf, allOf and not may be nested at most %d levels deep", MaxMatchNesting))}
+	}
+	for i := range mc.AnyOf {
+		allErrs = append(allErrs, validateMatchBranch(targetKind, &mc.AnyOf[i], fldPath.Child("anyOf").Index(i), depth+1)...)
+	}
+	for i := range mc.AllOf {
+		allErrs = append(allErrs, validateMatchBranch(targetKind, &mc.AllOf[i], fldPath.Child("allOf").Index(i), depth+1)...)
+	}
+	if mc.Not != nil {
+		allErrs = append(allErrs, validateMatchBranch(targetKind, mc.Not, fldPath.Child("not"), depth+1)...)
+	}
+	return allErrs
+}
+
+// validateMatchBranch validates criteria nested in a group. A branch without criteria matches every target, which
+// is what a misspelled criteria block decodes to, so it is rejected.
+func validateMatchBranch(targetKind string, mc *autolabellerv1alpha1.MatchCriteria, fldPath *field.Path, depth int) field.ErrorList {
+	if equality.Semantic.DeepEqual(*mc, autolabellerv1alpha1.MatchCriteria{}) {
+		return field.ErrorList{field.Required(fldPath, "nested criteria must set at least one criterion")}
+	}
+	return validateMatchCriteria(targetKind, mc, fldPath, depth)
+}
+
+// validateEnum checks value against the values allowed by the CRD schema, which does not reach criteria nested in
+// anyOf, allOf and not. Empty values are left unset.
+func validateEnum(value string, allowed []string, fldPath *field.Path) field.ErrorList {
+	if value == "" || slices.Contains(allowed, value) {
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, value, allowed)}
+}
+
+// validateEnumList applies validateEnum to every item of a list whose items are an enum in the CRD schema.
+func validateEnumList(values, allowed []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, v := range values {
+		if v == "" {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), v, allowed))
+			continue
+		}
+		allErrs = append(allErrs, validateEnum(v, allowed, fldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+// validateMatchCriteria validates one level of criteria and recurses into its groups; depth counts the
+// enclosing groups.
+func validateMatchCriteria(targetKind string, mc *autolabellerv1alpha1.MatchCriteria, fldPath *field.Path, depth int) field.ErrorList {
+	var allErrs field.ErrorList
+	if mc == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateMatchGroups(targetKind, mc, fldPath, depth)...)
+
+	// Kind-specific blocks and the TargetKinds each one applies to
+	blocks := []struct {
+		name  string
+		kinds []string
+		set   bool
+	}{
+		{"podMatch", PodTemplateKinds, mc.PodMatch != nil},
+		{"nodeMatch", []string{"Node"}, mc.NodeMatch != nil},
+		{"deploymentMatch", []string{"Deployment"}, mc.DeploymentMatch != nil},
+		{"namespaceMatch", []string{"Namespace"}, mc.NamespaceMatch != nil},
+		{"serviceMatch", []string{"Service"}, mc.ServiceMatch != nil},
+		{"statefulSetMatch", []string{"StatefulSet"}, mc.StatefulSetMatch != nil},
+		{"daemonSetMatch", []string{"DaemonSet"}, mc.DaemonSetMatch != nil},
+		{"replicaSetMatch", []string{"ReplicaSet"}, mc.ReplicaSetMatch != nil},
+		{"jobMatch", []string{"Job", "CronJob"}, mc.JobMatch != nil},
+		{"cronJobMatch", []string{"CronJob"}, mc.CronJobMatch != nil},
+	}
+	for _, b := range blocks {
+		if b.set && !slices.Contains(b.kinds, targetKind) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(b.name),
+				fmt.Sprintf("%s criteria can only be used with targetKind %s, not %s", b.name, strings.Join(b.kinds, ", "), targetKind)))
+		}
+	}
+
+	if cm := mc.CommonMatch; cm != nil {
+		allErrs = append(allErrs, ValidateLabelSet(cm.Labels, fldPath.Child("commonMatch", "labels"))...)
+		allErrs = append(allErrs, validateMetadataRequirements(cm.LabelRequirements, true, fldPath.Child("commonMatch", "labelRequirements"))...)
+		allErrs = append(allErrs, validateMetadataRequirements(cm.AnnotationRequirements, false, fldPath.Child("commonMatch", "annotationRequirements"))...)
+		for _, k := range slices.Sorted(maps.Keys(cm.Annotations)) {
+			if pattern, ok := strings.CutPrefix(cm.Annotations[k], annotationRegexPrefix); ok {
+				if _, err := compileAnchored(pattern); err != nil {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("commonMatch", "annotations").Key(k), cm.Annotations[k], err.Error()))
+				}
+			}
+		}
+		if cm.NamespaceSelector != nil {
+			allErrs = append(allErrs, metav1validation.ValidateLabelSelector(cm.NamespaceSelector,
+				metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("commonMatch", "namespaceSelector"))...)
+		}
+	}
+	for i, req := range mc.FieldMatch {
+		allErrs = append(allErrs, validateFieldRequirement(req, fldPath.Child("fieldMatch").Index(i))...)
+	}
+	if len(mc.Expression) > MaxExpressionLength {
+		allErrs = append(allErrs, field.TooLong(fldPath.Child("expression"), "", MaxExpressionLength))
+	} else if mc.Expression != "" {
+		if _, err := CompileExpression(mc.Expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("expression"), mc.Expression, err.Error()))
+		}
+	}
+	if pm := mc.PodMatch; pm != nil {
+		p := fldPath.Child("podMatch")
+		allErrs = append(allErrs, validateQuantityExpression(pm.CPURequests, p.Child("cpuRequests"))...)
+		allErrs = append(allErrs, validateQuantityExpression(pm.MemoryRequests, p.Child("memoryRequests"))...)
+		allErrs = append(allErrs, validateQuantityExpression(pm.CPULimits, p.Child("cpuLimits"))...)
+		allErrs = append(allErrs, validateQuantityExpression(pm.MemoryLimits, p.Child("memoryLimits"))...)
+		allErrs = append(allErrs, validateEnum(pm.MissingResourcePolicy, []string{MissingResourceZero, MissingResourceNoMatch}, p.Child("missingResourcePolicy"))...)
+		if pm.Security != nil {
+			allErrs = append(allErrs, validatePodSecurity(pm.Security, p.Child("security"))...)
+		}
+		if st := pm.Status; st != nil {
+			if targetKind != "Pod" {
+				allErrs = append(allErrs, field.Forbidden(p.Child("status"), "pod status is only available for Pod targets"))
+			}
+			allErrs = append(allErrs, validateIntExpression(st.RestartCount, p.Child("status", "restartCount"))...)
+			allErrs = append(allErrs, validateEnumList(st.Phases, []string{string(corev1.PodPending), string(corev1.PodRunning),
+				string(corev1.PodSucceeded), string(corev1.PodFailed), string(corev1.PodUnknown)}, p.Child("status", "phases"))...)
+			allErrs = append(allErrs, validateEnumList(st.QOSClasses, []string{string(corev1.PodQOSGuaranteed), string(corev1.PodQOSBurstable),
+				string(corev1.PodQOSBestEffort)}, p.Child("status", "qosClasses"))...)
+			if st.PendingFor != "" {
+				if _, err := parseDurationExpression(st.PendingFor); err != nil {
+					allErrs = append(allErrs, field.Invalid(p.Child("status", "pendingFor"), st.PendingFor, err.Error()))
+				}
+			}
+		}
+	}
+	if nm := mc.NodeMatch; nm != nil {
+		p := fldPath.Child("nodeMatch")
+		for i, taint := range nm.Taints {
+			if err := validateTaint(taint); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("taints").Index(i), taint, err.Error()))
+			}
+		}
+		if isVersionComparison(nm.KernelVersion) {
+			allErrs = append(allErrs, validateVersionExpression(nm.KernelVersion, p.Child("kernelVersion"))...)
+		}
+		allErrs = append(allErrs, validateVersionExpression(nm.ContainerRuntimeVersion, p.Child("containerRuntimeVersion"))...)
+		allErrs = append(allErrs, validateVersionExpression(nm.KubeletVersion, p.Child("kubeletVersion"))...)
+		for i, cond := range nm.Conditions {
+			if _, _, err := ParseNodeCondition(cond); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("conditions").Index(i), cond, err.Error()))
+			}
+		}
+		// Label values are pushed to the API server in label selectors
+		labelValues := []struct {
+			name   string
+			values []string
+		}{
+			{"legacyArchLabels", nm.LegacyArchLabels}, {"legacyOSLabels", nm.LegacyOSLabels}, {"zones", nm.Zones}, {"regions", nm.Regions},
+		}
+		for _, lv := range labelValues {
+			for i, v := range lv.values {
+				for _, msg := range validation.IsValidLabelValue(v) {
+					allErrs = append(allErrs, field.Invalid(p.Child(lv.name).Index(i), v, msg))
+				}
+			}
+		}
+		allErrs = append(allErrs, validateResourceExpressions(nm.Capacity, p.Child("capacity"))...)
+		allErrs = append(allErrs, validateResourceExpressions(nm.Allocatable, p.Child("allocatable"))...)
+	}
+	if dm := mc.DeploymentMatch; dm != nil {
+		allErrs = append(allErrs, validateIntExpression(dm.Replicas, fldPath.Child("deploymentMatch", "replicas"))...)
+	}
+	if sm := mc.ServiceMatch; sm != nil {
+		p := fldPath.Child("serviceMatch")
+		for i, port := range sm.Ports {
+			if _, _, err := ParseServicePort(port); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("ports").Index(i), port, err.Error()))
+			}
+		}
+		allErrs = append(allErrs, ValidateLabelSet(sm.Selector, p.Child("selector"))...)
+		allErrs = append(allErrs, validateEnum(sm.Type, []string{string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort),
+			string(corev1.ServiceTypeLoadBalancer), string(corev1.ServiceTypeExternalName)}, p.Child("type"))...)
+		allErrs = append(allErrs, validateEnum(sm.ExternalTrafficPolicy, []string{string(corev1.ServiceExternalTrafficPolicyCluster),
+			string(corev1.ServiceExternalTrafficPolicyLocal)}, p.Child("externalTrafficPolicy"))...)
+		allErrs = append(allErrs, validateEnumList(sm.Protocols, []string{string(corev1.ProtocolTCP), string(corev1.ProtocolUDP),
+			string(corev1.ProtocolSCTP)}, p.Child("protocols"))...)
+	}
+	if sm := mc.StatefulSetMatch; sm != nil {
+		p := fldPath.Child("statefulSetMatch")
+		allErrs = append(allErrs, validateIntExpression(sm.Replicas, p.Child("replicas"))...)
+		allErrs = append(allErrs, validateEnum(sm.PodManagementPolicy, []string{string(appsv1.OrderedReadyPodManagement),
+			string(appsv1.ParallelPodManagement)}, p.Child("podManagementPolicy"))...)
+	}
+	if dm := mc.DaemonSetMatch; dm != nil {
+		p := fldPath.Child("daemonSetMatch")
+		allErrs = append(allErrs, ValidateLabelSet(dm.NodeSelector, p.Child("nodeSelector"))...)
+		allErrs = append(allErrs, validateEnum(dm.UpdateStrategy, []string{string(appsv1.RollingUpdateDaemonSetStrategyType),
+			string(appsv1.OnDeleteDaemonSetStrategyType)}, p.Child("updateStrategy"))...)
+		for i, toleration := range dm.Tolerations {
+			if _, err := ParseTolerationCriterion(toleration); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("tolerations").Index(i), toleration, err.Error()))
+			}
+		}
+	}
+	if rm := mc.ReplicaSetMatch; rm != nil {
+		allErrs = append(allErrs, validateIntExpression(rm.Replicas, fldPath.Child("replicaSetMatch", "replicas"))...)
+	}
+	if jm := mc.JobMatch; jm != nil {
+		p := fldPath.Child("jobMatch")
+		allErrs = append(allErrs, validateIntExpression(jm.BackoffLimit, p.Child("backoffLimit"))...)
+		allErrs = append(allErrs, validateIntExpression(jm.Completions, p.Child("completions"))...)
+		allErrs = append(allErrs, validateIntExpression(jm.Parallelism, p.Child("parallelism"))...)
+		allErrs = append(allErrs, validateIntExpression(jm.ActiveDeadlineSeconds, p.Child("activeDeadlineSeconds"))...)
+		allErrs = append(allErrs, validateIntExpression(jm.TTLSecondsAfterFinished, p.Child("ttlSecondsAfterFinished"))...)
+		allErrs = append(allErrs, validateEnum(jm.Status, []string{JobStatusActive, JobStatusComplete, JobStatusFailed}, p.Child("status"))...)
+		if jm.Status != "" && targetKind == "CronJob" {
+			allErrs = append(allErrs, field.Forbidden(p.Child("status"), "job status is not available for CronJob targets"))
+		}
+	}
+	if cm := mc.CronJobMatch; cm != nil {
+		p := fldPath.Child("cronJobMatch")
+		allErrs = append(allErrs, validateEnum(cm.ConcurrencyPolicy, []string{string(batchv1.AllowConcurrent),
+			string(batchv1.ForbidConcurrent), string(batchv1.ReplaceConcurrent)}, p.Child("concurrencyPolicy"))...)
+		if cm.Schedule != "" {
+			if _, err := ScheduleInterval(cm.Schedule); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("schedule"), cm.Schedule, err.Error()))
+			}
+		}
+		if cm.ScheduleInterval != "" {
+			if _, err := parseDurationExpression(cm.ScheduleInterval); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("scheduleInterval"), cm.ScheduleInterval, err.Error()))
+			}
+		}
+	}
+	if nm := mc.NamespaceMatch; nm != nil {
+		p := fldPath.Child("namespaceMatch")
+		allErrs = append(allErrs, validateEnum(nm.Phase, []string{string(corev1.NamespaceActive), string(corev1.NamespaceTerminating)}, p.Child("phase"))...)
+		for i, key := range nm.HasLabels {
+			for _, msg := range validation.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(p.Child("hasLabels").Index(i), key, msg))
+			}
+		}
+		if nm.Age != "" {
+			if _, err := parseDurationExpression(nm.Age); err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("age"), nm.Age, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateLabelSet checks that every key and value in labels follows Kubernetes label syntax.
+func ValidateLabelSet(labels map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		v := labels[k]
+		for _, msg := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(k), v, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateFieldRequirement(req autolabellerv1alpha1.FieldRequirement, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if req.Path == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("path"), ""))
+	} else if _, err := ParseFieldPath(req.Path); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), req.Path, err.Error()))
+	}
+	switch req.Operator {
+	case "", FieldOpEquals, FieldOpIn, FieldOpExists, FieldOpDoesNotExist, FieldOpRegex, FieldOpNumeric, FieldOpQuantity:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("operator"), req.Operator,
+			[]string{FieldOpEquals, FieldOpIn, FieldOpExists, FieldOpDoesNotExist, FieldOpRegex, FieldOpNumeric, FieldOpQuantity}))
+	}
+	switch req.Match {
+	case "", FieldMatchAny, FieldMatchAll:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("match"), req.Match, []string{FieldMatchAny, FieldMatchAll}))
+	}
+	if len(req.Values) > 0 && req.Operator != FieldOpIn {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("values"), "only used by the In operator"))
+	}
+	switch req.Operator {
+	case FieldOpExists, FieldOpDoesNotExist:
+		if req.Value != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("value"), fmt.Sprintf("not used by the %s operator", req.Operator)))
+		}
+	case FieldOpIn:
+		if len(req.Values) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("values"), "the In operator needs at least one value"))
+		}
+		if req.Value != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("value"), "not used by the In operator, use values"))
+		}
+	case FieldOpRegex:
+		if _, err := compileAnchored(req.Value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), req.Value, err.Error()))
+		}
+	case FieldOpNumeric:
+		if _, err := parseNumericExpression(req.Value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), req.Value, err.Error()))
+		}
+	case FieldOpQuantity:
+		if req.Value == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("value"), "the Quantity operator needs a comparison expression"))
+		}
+		allErrs = append(allErrs, validateQuantityExpression(req.Value, fldPath.Child("value"))...)
+	}
+	return allErrs
+}
+
+func validateQuantityExpression(expr string, fldPath *field.Path) field.ErrorList {
+	if expr == "" {
+		return nil
+	}
+	if _, err := parseQuantityExpression(expr); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, expr, err.Error())}
+	}
+	return nil
+}
+
+// validateResourceExpressions checks the resource names and quantity expressions of nodeMatch capacity or allocatable.
+func validateResourceExpressions(exprs map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, name := range slices.Sorted(maps.Keys(exprs)) {
+		for _, msg := range validation.IsQualifiedName(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), name, msg))
+		}
+		if exprs[name] == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Key(name), "a quantity comparison expression is required"))
+		}
+		allErrs = append(allErrs, validateQuantityExpression(exprs[name], fldPath.Key(name))...)
+	}
+	return allErrs
+}
+
+func validateIntExpression(expr string, fldPath *field.Path) field.ErrorList {
+	if expr == "" {
+		return nil
+	}
+	if _, err := ParseIntExpression(expr); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, expr, err.Error())}
+	}
+	return nil
+}
+
+func validateVersionExpression(expr string, fldPath *field.Path) field.ErrorList {
+	if expr == "" {
+		return nil
+	}
+	if _, err := parseVersionExpression(expr); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, expr, err.Error())}
+	}
+	return nil
+}
+
+// validateTaint checks the key=value:effect form matched against node taints. The value may be empty.
+func validateTaint(taint string) error {
+	kv, effect, ok := strings.Cut(taint, ":")
+	if !ok {
+		return fmt.Errorf("taint must have the form key=value:effect")
+	}
+	key, value, ok := strings.Cut(kv, "=")
+	if !ok {
+		return fmt.Errorf("taint must have the form key=value:effect")
+	}
+	if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+		return fmt.Errorf("invalid taint key %q: %s", key, strings.Join(msgs, "; "))
+	}
+	if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+		return fmt.Errorf("invalid taint value %q: %s", value, strings.Join(msgs, "; "))
+	}
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		return nil
+	}
+	return fmt.Errorf("invalid taint effect %q, must be one of NoSchedule, PreferNoSchedule, NoExecute", effect)
+}
@@ -0,0 +1,151 @@
+package matchinglogic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+// versionPattern captures the dotted numeric core of a version string and, for distro kernels, the numeric
+// ABI/build suffix that follows it: "5.15.0-1057-azure" yields "5.15.0" and "1057".
+var versionPattern = regexp.MustCompile(`^[vV]?(\d+(?:\.\d+)*)(?:-(\d+))?`)
+
+// Version is a version reduced to its numeric components, e.g. [5 15 0 1057] for "5.15.0-1057-azure".
+type Version []int64
+
+// ParseVersion extracts the comparable components of a kernel, runtime or kubelet version string.
+// A leading "v" is ignored, as is any trailing text after the numeric part ("-azure", "-eks-1a2b3c").
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a valid version", s)
+	}
+	parts := strings.Split(m[1], ".")
+	if m[2] != "" {
+		parts = append(parts, m[2])
+	}
+	v := make(Version, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid version: %w", s, err)
+		}
+		v = append(v, n)
+	}
+	return v, nil
+}
+
+// compareTo compares v with other up to the precision of other, so "5.15.0-1057" equals "5.15" and
+// ">5.15" means 5.16 or later. Components missing from v count as zero.
+func (v Version) compareTo(other Version) int {
+	for i, want := range other {
+		var have int64
+		if i < len(v) {
+			have = v[i]
+		}
+		switch {
+		case have < want:
+			return -1
+		case have > want:
+			return 1
+		}
+	}
+	return 0
+}
+
+// MatchVersion reports whether actual satisfies the version expression expr, e.g. ">=5.15", "<1.7" or
+// "5.10..5.15". Operands are compared up to their own precision (see Version.compareTo).
+func MatchVersion(expr, actual string) (bool, error) {
+	cmp, err := parseVersionExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	have, err := ParseVersion(actual)
+	if err != nil {
+		return false, err
+	}
+	return cmp.evaluate(func(operand string) (int, error) {
+		want, err := ParseVersion(operand)
+		if err != nil {
+			return 0, err
+		}
+		return have.compareTo(want), nil
+	})
+}
+
+// parseVersionExpression parses expr and checks that every operand is a version.
+func parseVersionExpression(expr string) (Comparison, error) {
+	cmp, err := ParseComparison(expr)
+	if err != nil {
+		return Comparison{}, err
+	}
+	for _, operand := range []string{cmp.Value, cmp.Upper} {
+		if operand == "" {
+			continue
+		}
+		if _, err := ParseVersion(operand); err != nil {
+			return Comparison{}, err
+		}
+	}
+	if err := cmp.checkRange(func(low, high string) int {
+		l, _ := ParseVersion(low)
+		h, _ := ParseVersion(high)
+		return l.compareTo(h)
+	}); err != nil {
+		return Comparison{}, err
+	}
+	return cmp, nil
+}
+
+// isVersionComparison reports whether expr uses an operator or range rather than a bare value.
+func isVersionComparison(expr string) bool {
+	return hasOperator(expr) || strings.Contains(expr, OpRange)
+}
+
+// runtimeVersion strips the runtime name from a NodeInfo.ContainerRuntimeVersion such as "containerd://1.7.13".
+func runtimeVersion(s string) string {
+	if _, v, ok := strings.Cut(s, "://"); ok {
+		return v
+	}
+	return s
+}
+
+// matchNodeVersions evaluates the kernel, container runtime and kubelet version criteria of nm against node.
+// A bare KernelVersion keeps its substring semantics ("azure", "5.15.0-1057"); operators and ranges compare
+// semantically. Unparseable node versions never match.
+func matchNodeVersions(nm *autolabellerv1alpha1.NodeMatchCriteria, node *corev1.Node) (bool, []string) {
+	matchedFields := []string{}
+	info := node.Status.NodeInfo
+	if nm.KernelVersion != "" {
+		if isVersionComparison(nm.KernelVersion) {
+			if ok, err := MatchVersion(nm.KernelVersion, info.KernelVersion); err != nil || !ok {
+				return false, matchedFields
+			}
+		} else if !strings.Contains(info.KernelVersion, nm.KernelVersion) {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("nodeMatch.kernelVersion:%s", info.KernelVersion))
+	}
+
+	criteria := []struct {
+		field, expr, actual string
+	}{
+		{"nodeMatch.containerRuntimeVersion", nm.ContainerRuntimeVersion, runtimeVersion(info.ContainerRuntimeVersion)},
+		{"nodeMatch.kubeletVersion", nm.KubeletVersion, info.KubeletVersion},
+	}
+	for _, c := range criteria {
+		if c.expr == "" {
+			continue
+		}
+		if ok, err := MatchVersion(c.expr, c.actual); err != nil || !ok {
+			return false, matchedFields
+		}
+		matchedFields = append(matchedFields, fmt.Sprintf("%s:%s", c.field, c.actual))
+	}
+	return true, matchedFields
+}
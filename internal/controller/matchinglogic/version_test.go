@@ -0,0 +1,75 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Version comparisons", func() {
+	DescribeTable("ParseVersion",
+		func(s string, expected Version) {
+			v, err := ParseVersion(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal(expected))
+		},
+		Entry("azure kernel", "5.15.0-1057-azure", Version{5, 15, 0, 1057}),
+		Entry("debian kernel", "6.1.0-18-cloud-amd64", Version{6, 1, 0, 18}),
+		Entry("plain kernel", "6.8.12", Version{6, 8, 12}),
+		Entry("kubelet", "v1.30.2-eks-1a2b3c", Version{1, 30, 2}),
+		Entry("runtime", "1.7.13", Version{1, 7, 13}),
+	)
+
+	It("Should reject strings without a numeric version", func() {
+		_, err := ParseVersion("latest")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("MatchVersion",
+		func(expr, actual string, expected bool) {
+			ok, err := MatchVersion(expr, actual)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("at least 5.15", ">=5.15", "5.15.0-1057-azure", true),
+		Entry("at least 5.15 on 6.1", ">=5.15", "6.1.0-18-cloud-amd64", true),
+		Entry("at least 5.15 on 5.4", ">=5.15", "5.4.0-1100-azure", false),
+		Entry("numeric not lexical", ">5.9", "5.10.0", true),
+		Entry("greater than precision", ">5.15", "5.15.3", false),
+		Entry("ABI number", ">=5.15.0-1057", "5.15.0-1060-azure", true),
+		Entry("ABI number below", ">=5.15.0-1057", "5.15.0-1040-azure", false),
+		Entry("range", "5.10..6.1", "6.1.0-18-cloud-amd64", true),
+		Entry("range outside", "5.10..6.1", "6.8.0", false),
+		Entry("bare value is equality to precision", "1.30", "v1.30.2", true),
+		Entry("kubelet below", "<1.29", "v1.30.2", false),
+	)
+
+	It("Should reject inverted ranges", func() {
+		_, err := MatchVersion("6.1..5.10", "5.15.0")
+		Expect(err).To(MatchError(ContainSubstring("lower bound exceeds upper bound")))
+		_, err = MatchVersion("5.15.3..5.15", "5.15.3")
+		Expect(err).NotTo(HaveOccurred(), "bounds are compared up to the precision of the upper bound")
+	})
+
+	It("Should evaluate node kernel, runtime and kubelet versions", func() {
+		node := &corev1.Node{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+			KernelVersion:           "5.15.0-1057-azure",
+			ContainerRuntimeVersion: "containerd://1.7.13",
+			KubeletVersion:          "v1.30.2",
+		}}}
+		match := func(nm *autolabellerv1alpha1.NodeMatchCriteria) bool {
+			ok, _ := MatchesNodeDetailed(&autolabellerv1alpha1.MatchCriteria{NodeMatch: nm}, node, nil)
+			return ok
+		}
+
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{KernelVersion: ">=5.15"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{KernelVersion: "azure"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{KernelVersion: "<5.15"})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{ContainerRuntimeVersion: ">=1.7"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{ContainerRuntimeVersion: "<1.7"})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{KubeletVersion: "1.28..1.30"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.NodeMatchCriteria{KubeletVersion: "1.29"})).To(BeFalse())
+	})
+})
@@ -0,0 +1,105 @@
+package matchinglogic
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("Workload matching", func() {
+	template := func() corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			Tolerations: []corev1.Toleration{
+				{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoExecute},
+			},
+			Containers: []corev1.Container{{
+				Image: "registry.example.com/data/postgres:16",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				}},
+			}},
+		}}
+	}
+	podMatch := &autolabellerv1alpha1.PodMatchCriteria{Images: []string{"*/postgres"}, MemoryRequests: ">=1Gi"}
+
+	It("Should match StatefulSets on replicas, storage and pod template", func() {
+		sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](3),
+			Template: template(),
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: ptr.To("premium-ssd")},
+			}},
+		}}
+		mc := &autolabellerv1alpha1.MatchCriteria{
+			PodMatch: podMatch,
+			StatefulSetMatch: &autolabellerv1alpha1.StatefulSetMatchCriteria{
+				Replicas:                ">=3",
+				PodManagementPolicy:     "OrderedReady",
+				HasVolumeClaimTemplates: ptr.To(true),
+				VolumeClaimTemplates:    []string{"data"},
+				StorageClass:            "premium-*",
+			},
+		}
+		ok, fields := MatchesStatefulSetDetailed(mc, sts, nil)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(ContainElements("statefulSetMatch.storageClass", "podMatch.images:*/postgres", "podMatch.memoryRequests:2Gi"))
+
+		mc.StatefulSetMatch.StorageClass = "standard"
+		ok, _ = MatchesStatefulSetDetailed(mc, sts, nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should match DaemonSets on strategy, node selector and tolerations", func() {
+		ds := &appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: template()}}
+		match := func(dm *autolabellerv1alpha1.DaemonSetMatchCriteria) bool {
+			ok, _ := MatchesDaemonSetDetailed(&autolabellerv1alpha1.MatchCriteria{DaemonSetMatch: dm}, ds, nil)
+			return ok
+		}
+		Expect(match(&autolabellerv1alpha1.DaemonSetMatchCriteria{UpdateStrategy: "RollingUpdate"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.DaemonSetMatchCriteria{UpdateStrategy: "OnDelete"})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.DaemonSetMatchCriteria{NodeSelector: map[string]string{"kubernetes.io/os": "linux"}})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.DaemonSetMatchCriteria{Tolerations: []string{
+			"node-role.kubernetes.io/control-plane:NoSchedule", "dedicated=gpu:NoExecute", "dedicated",
+		}})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.DaemonSetMatchCriteria{Tolerations: []string{"dedicated=gpu:NoSchedule"}})).To(BeFalse())
+		Expect(match(&autolabellerv1alpha1.DaemonSetMatchCriteria{Tolerations: []string{"dedicated=cpu"}})).To(BeFalse())
+	})
+
+	It("Should match ReplicaSets on their controller owner", func() {
+		rs := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: ptr.To(true),
+			}}},
+			Spec: appsv1.ReplicaSetSpec{Replicas: ptr.To[int32](0), Template: template()},
+		}
+		match := func(rm *autolabellerv1alpha1.ReplicaSetMatchCriteria) bool {
+			ok, _ := MatchesReplicaSetDetailed(&autolabellerv1alpha1.MatchCriteria{ReplicaSetMatch: rm}, rs, nil)
+			return ok
+		}
+		Expect(match(&autolabellerv1alpha1.ReplicaSetMatchCriteria{HasOwner: ptr.To(true), OwnerKind: "Deployment", OwnerName: "w*"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ReplicaSetMatchCriteria{Replicas: "==0"})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ReplicaSetMatchCriteria{OwnerName: "api"})).To(BeFalse())
+		rs.OwnerReferences = nil
+		Expect(match(&autolabellerv1alpha1.ReplicaSetMatchCriteria{HasOwner: ptr.To(false)})).To(BeTrue())
+		Expect(match(&autolabellerv1alpha1.ReplicaSetMatchCriteria{OwnerKind: "Deployment"})).To(BeFalse())
+	})
+
+	It("Should evaluate podMatch against Deployment templates", func() {
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: template()}}
+		ok, _ := MatchesDeploymentDetailed(&autolabellerv1alpha1.MatchCriteria{PodMatch: podMatch}, deployment, nil)
+		Expect(ok).To(BeTrue())
+		ok, _ = MatchesDeploymentDetailed(&autolabellerv1alpha1.MatchCriteria{
+			PodMatch: &autolabellerv1alpha1.PodMatchCriteria{Images: []string{"nginx"}},
+		}, deployment, nil)
+		Expect(ok).To(BeFalse())
+	})
+})
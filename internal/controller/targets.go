@@ -0,0 +1,276 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/helpers"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/matchinglogic"
+)
+
+// supportedTargetKinds lists every TargetKind the reconciler knows how to label.
+var supportedTargetKinds = []string{"Pod", "Node", "Deployment", "Namespace", "Service", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob"}
+
+// targetGVK returns the GroupVersionKind for a rule's target. An empty apiVersion selects the built-in kind's own
+// apiVersion; it stays empty for other kinds, which targetScope reports as unresolvable.
+func targetGVK(apiVersion, kind string) schema.GroupVersionKind {
+	if apiVersion == "" {
+		apiVersion, _ = matchinglogic.BuiltinTargetAPIVersion(kind)
+	}
+	return schema.FromAPIVersionAndKind(apiVersion, kind)
+}
+
+// ruleTargetGVK returns the GroupVersionKind of the objects rule labels.
+func ruleTargetGVK(rule *autolabellerv1alpha1.ClassificationRule) schema.GroupVersionKind {
+	return targetGVK(rule.Spec.TargetAPIVersion, rule.Spec.TargetKind)
+}
+
+// isBuiltinGVK reports whether gvk has typed support; other kinds are handled as unstructured objects.
+func isBuiltinGVK(gvk schema.GroupVersionKind) bool {
+	return matchinglogic.IsBuiltinTarget(gvk.GroupVersion().String(), gvk.Kind)
+}
+
+// isClusterScopedKind reports whether targets of kind have no namespace, so commonMatch.namespace does not apply.
+func isClusterScopedKind(kind string) bool {
+	return kind == "Node" || kind == "Namespace"
+}
+
+// newTargetObject returns an empty object for the given TargetKind, or nil if the kind is not supported.
+func newTargetObject(kind string) client.Object {
+	switch kind {
+	case "Pod":
+		return &corev1.Pod{}
+	case "Node":
+		return &corev1.Node{}
+	case "Deployment":
+		return &appsv1.Deployment{}
+	case "Namespace":
+		return &corev1.Namespace{}
+	case "Service":
+		return &corev1.Service{}
+	case "StatefulSet":
+		return &appsv1.StatefulSet{}
+	case "DaemonSet":
+		return &appsv1.DaemonSet{}
+	case "ReplicaSet":
+		return &appsv1.ReplicaSet{}
+	case "Job":
+		return &batchv1.Job{}
+	case "CronJob":
+		return &batchv1.CronJob{}
+	}
+	return nil
+}
+
+// newTargetList returns an empty list object for the given TargetKind, or nil if the kind is not supported.
+func newTargetList(kind string) client.ObjectList {
+	switch kind {
+	case "Pod":
+		return &corev1.PodList{}
+	case "Node":
+		return &corev1.NodeList{}
+	case "Deployment":
+		return &appsv1.DeploymentList{}
+	case "Namespace":
+		return &corev1.NamespaceList{}
+	case "Service":
+		return &corev1.ServiceList{}
+	case "StatefulSet":
+		return &appsv1.StatefulSetList{}
+	case "DaemonSet":
+		return &appsv1.DaemonSetList{}
+	case "ReplicaSet":
+		return &appsv1.ReplicaSetList{}
+	case "Job":
+		return &batchv1.JobList{}
+	case "CronJob":
+		return &batchv1.CronJobList{}
+	}
+	return nil
+}
+
+// newTargetListFor returns an empty list for gvk: the typed list for built-in kinds, an unstructured one otherwise.
+func newTargetListFor(gvk schema.GroupVersionKind) client.ObjectList {
+	if isBuiltinGVK(gvk) {
+		return newTargetList(gvk.Kind)
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return list
+}
+
+// targetListOptions returns the server-side pre-filters for listing targets of gvk.
+// Criteria that need in-memory inspection are evaluated afterwards by matchTarget.
+func targetListOptions(gvk schema.GroupVersionKind, clusterScoped bool, match *autolabellerv1alpha1.MatchCriteria) []client.ListOption {
+	listOpts := []client.ListOption{}
+	match = helpers.PrefilterCriteria(match)
+	if !isBuiltinGVK(gvk) {
+		helpers.FilterUnstructuredList(&listOpts, match, clusterScoped)
+		return listOpts
+	}
+	switch gvk.Kind {
+	case "Pod":
+		helpers.FilterPodList(&listOpts, match)
+	case "Node":
+		helpers.FilterNodeList(&listOpts, match)
+	case "Deployment":
+		helpers.FilterDeploymentList(&listOpts, match)
+	case "Namespace":
+		helpers.FilterNamespaceList(&listOpts, match)
+	case "Service":
+		helpers.FilterServiceList(&listOpts, match)
+	case "StatefulSet":
+		helpers.FilterStatefulSetList(&listOpts, match)
+	case "DaemonSet":
+		helpers.FilterDaemonSetList(&listOpts, match)
+	case "ReplicaSet":
+		helpers.FilterReplicaSetList(&listOpts, match)
+	case "Job":
+		helpers.FilterJobList(&listOpts, match)
+	case "CronJob":
+		helpers.FilterCronJobList(&listOpts, match)
+	}
+	return listOpts
+}
+
+// matchTarget evaluates the rule's match criteria against obj, dispatching on its concrete type.
+// env carries the cluster state some criteria need (see loadEnvironment).
+func matchTarget(match *autolabellerv1alpha1.MatchCriteria, obj client.Object, env *matchinglogic.Environment) (bool, []string) {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return matchinglogic.MatchesPodDetailed(match, o, env)
+	case *corev1.Node:
+		return matchinglogic.MatchesNodeDetailed(match, o, env)
+	case *appsv1.Deployment:
+		return matchinglogic.MatchesDeploymentDetailed(match, o, env)
+	case *corev1.Namespace:
+		return matchinglogic.MatchesNamespaceDetailed(match, o, env)
+	case *corev1.Service:
+		return matchinglogic.MatchesServiceDetailed(match, o, env)
+	case *appsv1.StatefulSet:
+		return matchinglogic.MatchesStatefulSetDetailed(match, o, env)
+	case *appsv1.DaemonSet:
+		return matchinglogic.MatchesDaemonSetDetailed(match, o, env)
+	case *appsv1.ReplicaSet:
+		return matchinglogic.MatchesReplicaSetDetailed(match, o, env)
+	case *batchv1.Job:
+		return matchinglogic.MatchesJobDetailed(match, o, env)
+	case *batchv1.CronJob:
+		return matchinglogic.MatchesCronJobDetailed(match, o, env)
+	case *unstructured.Unstructured:
+		return matchinglogic.MatchesUnstructuredDetailed(match, o, env)
+	}
+	return false, nil
+}
+
+// targetScope reports whether objects of gvk are cluster-scoped. Built-in kinds are known up front; other kinds
+// are looked up through discovery, which fails with a no-match error while the kind is not served.
+func (r *ClassificationRuleReconciler) targetScope(gvk schema.GroupVersionKind) (bool, error) {
+	if isBuiltinGVK(gvk) {
+		return isClusterScopedKind(gvk.Kind), nil
+	}
+	if gvk.Version == "" {
+		return false, fmt.Errorf("targetAPIVersion is required for targetKind %s", gvk.Kind)
+	}
+	mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// ensureTargetWatch starts watching gvk the first time a rule targets a kind without typed support, so its
+// objects trigger reconciliation like the built-in kinds do. Watches are kept for the lifetime of the manager.
+func (r *ClassificationRuleReconciler) ensureTargetWatch(gvk schema.GroupVersionKind) error {
+	if isBuiltinGVK(gvk) || r.controller == nil {
+		return nil
+	}
+	r.watchMu.Lock()
+	defer r.watchMu.Unlock()
+	if _, ok := r.watchedKinds[gvk]; ok {
+		return nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.controller.Watch(source.Kind[client.Object](r.cache, obj, handler.EnqueueRequestsFromMapFunc(r.rulesForTarget(gvk)))); err != nil {
+		return err
+	}
+	if r.watchedKinds == nil {
+		r.watchedKinds = map[schema.GroupVersionKind]struct{}{}
+	}
+	r.watchedKinds[gvk] = struct{}{}
+	return nil
+}
+
+// listTargets lists the objects of gvk.
+func (r *ClassificationRuleReconciler) listTargets(ctx context.Context, gvk schema.GroupVersionKind, opts ...client.ListOption) ([]client.Object, error) {
+	list := newTargetListFor(gvk)
+	if list == nil {
+		return nil, fmt.Errorf("TargetKind %s not yet implemented", gvk.Kind)
+	}
+	if err := r.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// loadEnvironment collects the cluster state needed to evaluate match beyond the target objects themselves.
+// Only the parts the criteria actually use are loaded.
+func (r *ClassificationRuleReconciler) loadEnvironment(ctx context.Context, match *autolabellerv1alpha1.MatchCriteria) (*matchinglogic.Environment, error) {
+	env := &matchinglogic.Environment{}
+	if matchinglogic.NeedsNamespaceLabels(match) {
+		var namespaces corev1.NamespaceList
+		if err := r.List(ctx, &namespaces); err != nil {
+			return nil, fmt.Errorf("listing Namespaces: %w", err)
+		}
+		env.SelectedNamespaces = matchinglogic.SelectNamespaces(match, namespaces.Items)
+	}
+	if matchinglogic.NeedsQuotaNamespaces(match) {
+		var quotas corev1.ResourceQuotaList
+		if err := r.List(ctx, &quotas); err != nil {
+			return nil, fmt.Errorf("listing ResourceQuotas: %w", err)
+		}
+		env.QuotaNamespaces = make(map[string]struct{}, len(quotas.Items))
+		for _, q := range quotas.Items {
+			env.QuotaNamespaces[q.Namespace] = struct{}{}
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/helpers"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/matchinglogic"
+)
+
+// log is for logging in this package.
+var podlog = logf.Log.WithName("pod-resource")
+
+// SetupPodWebhookWithManager registers the labelling webhook for Pods in the manager.
+func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
+		WithDefaulter(&PodCustomDefaulter{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// The failure policy is Ignore so Pods are still admitted while the operator is unavailable; the reconciler labels
+// them shortly after. Deploy config/fail-closed when labels must be present from the first scheduling decision.
+// Namespaces are excluded by the webhook's namespaceSelector in config/webhook, so the API server never sends them.
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.kb.io,admissionReviewVersions=v1
+
+// PodCustomDefaulter injects the labels of every active Pod ClassificationRule that matches a Pod being created.
+// Labels are applied through the same conflict policy and ownership tracking as the reconciler, so the reconciler
+// sees them as already applied.
+type PodCustomDefaulter struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomDefaulter = &PodCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Pod.
+// Errors while reading rules are logged and the Pod is admitted unchanged, labelling never blocks Pod creation.
+func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+
+	// The namespace may only be known from the request when the Pod is created through a namespaced endpoint.
+	// Rules are matched against and labels applied to candidate, so a rule sees the labels of the rules before it;
+	// they are copied back to pod at the end.
+	candidate := pod.DeepCopy()
+	if candidate.Namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			candidate.Namespace = req.Namespace
+		}
+	}
+	var rules autolabellerv1alpha1.ClassificationRuleList
+	if err := d.Client.List(ctx, &rules); err != nil {
+		podlog.Error(err, "failed to list ClassificationRules, admitting pod unchanged", "namespace", candidate.Namespace)
+		return nil
+	}
+	// Rules run in owner key order. Labels a later rule adds can only make an earlier rule match once the
+	// controller reconciles it.
+	sort.Slice(rules.Items, func(i, j int) bool {
+		return helpers.RuleOwnerKey(&rules.Items[i]) < helpers.RuleOwnerKey(&rules.Items[j])
+	})
+
+	var namespace *corev1.Namespace
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		if rule.Spec.TargetKind != "Pod" || !matchinglogic.IsBuiltinTarget(rule.Spec.TargetAPIVersion, "Pod") || rule.Spec.Suspend || !rule.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// Pods have no status yet at admission; the controller applies status-based rules once it is reported
+		if matchinglogic.NeedsPodStatus(rule.Spec.Match) {
+			continue
+		}
+		env := &matchinglogic.Environment{}
+		if matchinglogic.NeedsNamespaceLabels(rule.Spec.Match) {
+			if namespace == nil {
+				namespace = &corev1.Namespace{}
+				if err := d.Client.Get(ctx, client.ObjectKey{Name: candidate.Namespace}, namespace); err != nil {
+					podlog.Error(err, "failed to get the pod's namespace, namespaceSelector criteria will not match", "namespace", candidate.Namespace)
+				}
+			}
+			env.SelectedNamespaces = matchinglogic.SelectNamespaces(rule.Spec.Match, []corev1.Namespace{*namespace})
+		}
+		ok, fields := matchinglogic.MatchesPodDetailed(rule.Spec.Match, candidate, env)
+		if !ok {
+			continue
+		}
+		changed, conflicts := helpers.ApplyLabelsToObject(candidate, helpers.RuleOwnerKey(rule), rule.Spec.Labels, rule.Spec.ConflictPolicy)
+		for _, c := range conflicts {
+			podlog.Info("label conflict", "rule", client.ObjectKeyFromObject(rule), "namespace", candidate.Namespace,
+				"key", c.Key, "existingValue", c.ExistingValue, "desiredValue", c.DesiredValue, "resolution", c.Resolution)
+		}
+		if changed {
+			podlog.Info("pod matched criteria, injecting labels", "rule", client.ObjectKeyFromObject(rule),
+				"namespace", candidate.Namespace, "name", pod.GetName(), "generateName", pod.GetGenerateName(), "matchedFields", fields)
+		}
+	}
+	pod.Labels, pod.Annotations = candidate.Labels, candidate.Annotations
+	return nil
+}
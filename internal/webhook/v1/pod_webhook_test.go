@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/helpers"
+)
+
+var _ = Describe("Pod Webhook", func() {
+	var (
+		pod       *corev1.Pod
+		defaulter *PodCustomDefaulter
+	)
+
+	newRule := func(name string, match *autolabellerv1alpha1.MatchCriteria, labels map[string]string) *autolabellerv1alpha1.ClassificationRule {
+		return &autolabellerv1alpha1.ClassificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "autolabeller-system"},
+			Spec: autolabellerv1alpha1.ClassificationRuleSpec{
+				TargetKind:     "Pod",
+				Match:          match,
+				Labels:         labels,
+				ConflictPolicy: "Merge",
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(autolabellerv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		suspended := newRule("suspended", nil, map[string]string{"suspended": "true"})
+		suspended.Spec.Suspend = true
+		nodeRule := newRule("nodes", nil, map[string]string{"node": "true"})
+		nodeRule.Spec.TargetKind = "Node"
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newRule("everything", nil, map[string]string{"classified": "true"}),
+			newRule("prod-only", &autolabellerv1alpha1.MatchCriteria{
+				CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Namespace: "prod"},
+			}, map[string]string{"env": "prod"}),
+			newRule("core-namespaces", &autolabellerv1alpha1.MatchCriteria{
+				CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "core"}},
+				},
+			}, map[string]string{"tier": "core"}),
+			newRule("not-running", &autolabellerv1alpha1.MatchCriteria{
+				Not: &autolabellerv1alpha1.MatchCriteria{PodMatch: &autolabellerv1alpha1.PodMatchCriteria{
+					Status: &autolabellerv1alpha1.PodStatusCriteria{Phases: []string{"Running"}},
+				}},
+			}, map[string]string{"health": "starting"}),
+			suspended,
+			nodeRule,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"tier": "core"}}},
+		).Build()
+		defaulter = &PodCustomDefaulter{Client: c}
+
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{GenerateName: "funnypod-", Namespace: "default"}}
+	})
+
+	It("should inject the labels of matching active Pod rules", func() {
+		Expect(defaulter.Default(context.Background(), pod)).To(Succeed())
+		Expect(pod.Labels).To(Equal(map[string]string{"classified": "true"}))
+		Expect(helpers.GetLabelOwnership(pod)).To(HaveKey("autolabeller-system/everything"))
+	})
+
+	It("should evaluate namespace criteria", func() {
+		pod.Namespace = "prod"
+		Expect(defaulter.Default(context.Background(), pod)).To(Succeed())
+		Expect(pod.Labels).To(Equal(map[string]string{"classified": "true", "env": "prod"}))
+	})
+
+	It("should evaluate namespace selectors against the pod's namespace", func() {
+		pod.Namespace = "payments"
+		Expect(defaulter.Default(context.Background(), pod)).To(Succeed())
+		Expect(pod.Labels).To(Equal(map[string]string{"classified": "true", "tier": "core"}))
+	})
+
+	It("should match later rules against the labels injected by earlier ones", func() {
+		selectsFrontend := func(name string, labels map[string]string) *autolabellerv1alpha1.ClassificationRule {
+			return newRule(name, &autolabellerv1alpha1.MatchCriteria{
+				CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Labels: map[string]string{"tier": "frontend"}},
+			}, labels)
+		}
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(autolabellerv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			selectsFrontend("a-exposed", map[string]string{"exposed": "true"}),
+			newRule("b-frontend", nil, map[string]string{"tier": "frontend"}),
+			selectsFrontend("c-cached", map[string]string{"cached": "true"}),
+		).Build()
+		defaulter = &PodCustomDefaulter{Client: c}
+
+		Expect(defaulter.Default(context.Background(), pod)).To(Succeed())
+		// a-exposed runs before b-frontend adds the label it selects; the controller applies it on its next reconcile
+		Expect(pod.Labels).To(Equal(map[string]string{"tier": "frontend", "cached": "true"}))
+		Expect(helpers.GetLabelOwnership(pod)).To(HaveLen(2))
+	})
+
+	It("should leave rules on the pod status to the controller", func() {
+		Expect(defaulter.Default(context.Background(), pod)).To(Succeed())
+		Expect(pod.Labels).NotTo(HaveKey("health"))
+	})
+})
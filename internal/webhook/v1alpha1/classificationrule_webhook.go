@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	sigsjson "sigs.k8s.io/json"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+	"github.com/Joe-Bresee/Autolabeller/internal/controller/matchinglogic"
+)
+
+// log is for logging in this package.
+var classificationrulelog = logf.Log.WithName("classificationrule-resource")
+
+// SetupClassificationRuleWebhookWithManager registers the webhook for ClassificationRule in the manager.
+func SetupClassificationRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&autolabellerv1alpha1.ClassificationRule{}).
+		WithValidator(&ClassificationRuleCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-autolabeller-autolabeller-github-com-v1alpha1-classificationrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=autolabeller.autolabeller.github.com,resources=classificationrules,verbs=create;update,versions=v1alpha1,name=vclassificationrule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClassificationRuleCustomValidator rejects ClassificationRules whose spec could never be evaluated:
+// criteria blocks that don't fit the TargetKind, malformed expressions, taints and labels, and bad durations.
+type ClassificationRuleCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClassificationRuleCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClassificationRule.
+func (v *ClassificationRuleCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	rule, ok := obj.(*autolabellerv1alpha1.ClassificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClassificationRule object but got %T", obj)
+	}
+	classificationrulelog.Info("Validation for ClassificationRule upon creation", "name", rule.GetName())
+
+	return validateClassificationRule(ctx, rule)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClassificationRule.
+// Updates that leave the spec untouched (finalizers, deletion) are always allowed so rules created before the
+// webhook existed can still be cleaned up.
+func (v *ClassificationRuleCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	rule, ok := newObj.(*autolabellerv1alpha1.ClassificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClassificationRule object for the newObj but got %T", newObj)
+	}
+	oldRule, ok := oldObj.(*autolabellerv1alpha1.ClassificationRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClassificationRule object for the oldObj but got %T", oldObj)
+	}
+	classificationrulelog.Info("Validation for ClassificationRule upon update", "name", rule.GetName())
+
+	if !rule.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldRule.Spec, rule.Spec) {
+		return nil, nil
+	}
+	return validateClassificationRule(ctx, rule)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClassificationRule.
+func (v *ClassificationRuleCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateClassificationRule(ctx context.Context, rule *autolabellerv1alpha1.ClassificationRule) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	allErrs := validateMatchFields(ctx, field.NewPath("spec", "match"))
+	allErrs = append(allErrs, validateClassificationRuleSpec(&rule.Spec, field.NewPath("spec"))...)
+	// The scope of other kinds is only known to the controller, which reports it in the rule's conditions
+	clusterScoped := matchinglogic.IsBuiltinTarget(rule.Spec.TargetAPIVersion, rule.Spec.TargetKind) &&
+		(rule.Spec.TargetKind == "Node" || rule.Spec.TargetKind == "Namespace")
+	if clusterScoped && rule.Spec.Match != nil && rule.Spec.Match.CommonMatch != nil {
+		if rule.Spec.Match.CommonMatch.Namespace != "" {
+			warnings = append(warnings, fmt.Sprintf("spec.match.commonMatch.namespace is ignored for %s targetKind", rule.Spec.TargetKind))
+		}
+		if rule.Spec.Match.CommonMatch.NamespaceSelector != nil {
+			warnings = append(warnings, fmt.Sprintf("spec.match.commonMatch.namespaceSelector is ignored for %s targetKind", rule.Spec.TargetKind))
+		}
+	}
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(autolabellerv1alpha1.GroupVersion.WithKind("ClassificationRule").GroupKind(), rule.Name, allErrs)
+}
+
+func validateClassificationRuleSpec(spec *autolabellerv1alpha1.ClassificationRuleSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.TargetAPIVersion == "" {
+		if _, ok := matchinglogic.BuiltinTargetAPIVersion(spec.TargetKind); !ok {
+			allErrs = append(allErrs, field.Required(fldPath.Child("targetAPIVersion"), fmt.Sprintf("required for targetKind %s", spec.TargetKind)))
+		}
+	} else if _, err := schema.ParseGroupVersion(spec.TargetAPIVersion); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("targetAPIVersion"), spec.TargetAPIVersion, err.Error()))
+	}
+	allErrs = append(allErrs, matchinglogic.ValidateMatchCriteria(spec.TargetAPIVersion, spec.TargetKind, spec.Match, fldPath.Child("match"))...)
+	allErrs = append(allErrs, matchinglogic.ValidateLabelSet(spec.Labels, fldPath.Child("labels"))...)
+	if spec.RefreshInterval != "" {
+		d, err := time.ParseDuration(spec.RefreshInterval)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("refreshInterval"), spec.RefreshInterval, "must be a valid duration (e.g. 30s, 5m)"))
+		case d <= 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("refreshInterval"), spec.RefreshInterval, "must be positive"))
+		}
+	}
+	return allErrs
+}
+
+// validateMatchFields rejects unknown fields in the match criteria of the admitted object. The API server prunes
+// them everywhere else, but keeps them in anyOf, allOf and not, whose nested criteria the CRD schema leaves open,
+// and decoding would silently drop them, e.g. a misspelled criteria block that leaves its branch matching everything.
+func validateMatchFields(ctx context.Context, fldPath *field.Path) field.ErrorList {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || len(req.Object.Raw) == 0 {
+		return nil
+	}
+	var raw struct {
+		Spec struct {
+			Match json.RawMessage `json:"match"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(req.Object.Raw, &raw); err != nil || len(raw.Spec.Match) == 0 {
+		return nil
+	}
+	strictErrs, err := sigsjson.UnmarshalStrict(raw.Spec.Match, &autolabellerv1alpha1.MatchCriteria{})
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(raw.Spec.Match), err.Error())}
+	}
+	var allErrs field.ErrorList
+	for _, strictErr := range strictErrs {
+		allErrs = append(allErrs, field.Forbidden(fldPath, strictErr.Error()))
+	}
+	return allErrs
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	autolabellerv1alpha1 "github.com/Joe-Bresee/Autolabeller/api/v1alpha1"
+)
+
+var _ = Describe("ClassificationRule Webhook", func() {
+	var (
+		obj       *autolabellerv1alpha1.ClassificationRule
+		oldObj    *autolabellerv1alpha1.ClassificationRule
+		validator ClassificationRuleCustomValidator
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &autolabellerv1alpha1.ClassificationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "default"},
+			Spec: autolabellerv1alpha1.ClassificationRuleSpec{
+				TargetKind: "Pod",
+				Labels:     map[string]string{"tier": "frontend"},
+				Match: &autolabellerv1alpha1.MatchCriteria{
+					PodMatch: &autolabellerv1alpha1.PodMatchCriteria{CPURequests: ">=500m"},
+				},
+				RefreshInterval: "5m",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = ClassificationRuleCustomValidator{}
+	})
+
+	Context("When creating ClassificationRule under Validating Webhook", func() {
+		It("Should admit a valid rule", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny match blocks that don't fit the targetKind", func() {
+			obj.Spec.Match.NodeMatch = &autolabellerv1alpha1.NodeMatchCriteria{OSLabels: []string{"linux"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.match.nodeMatch"))
+		})
+
+		It("Should deny malformed quantity expressions", func() {
+			obj.Spec.Match.PodMatch.MemoryRequests = ">lots"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.match.podMatch.memoryRequests"))
+		})
+
+		It("Should deny malformed taints", func() {
+			obj.Spec.TargetKind = "Node"
+			obj.Spec.Match = &autolabellerv1alpha1.MatchCriteria{
+				NodeMatch: &autolabellerv1alpha1.NodeMatchCriteria{Taints: []string{"dedicated=gpu:Sometimes"}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.match.nodeMatch.taints[0]"))
+		})
+
+		It("Should deny malformed replica expressions", func() {
+			obj.Spec.TargetKind = "Deployment"
+			obj.Spec.Match = &autolabellerv1alpha1.MatchCriteria{
+				DeploymentMatch: &autolabellerv1alpha1.DeploymentMatchCriteria{Replicas: ">three"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.match.deploymentMatch.replicas"))
+		})
+
+		It("Should deny expressions that do not compile", func() {
+			obj.Spec.Match.Expression = "object.spec.containers.exists(c, c.image =="
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.match.expression"))
+		})
+
+		It("Should deny invalid label keys and values", func() {
+			obj.Spec.Labels = map[string]string{"bad key": "ok", "tier": "not a value"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.labels"))
+		})
+
+		It("Should deny an unparseable refreshInterval", func() {
+			obj.Spec.RefreshInterval = "soon"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.refreshInterval"))
+		})
+
+		It("Should require targetAPIVersion for kinds that are not built in", func() {
+			obj.Spec.TargetKind = "Certificate"
+			obj.Spec.Match = nil
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.targetAPIVersion"))
+
+			obj.Spec.TargetAPIVersion = "cert-manager.io/v1"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should only allow shared criteria for kinds that are not built in", func() {
+			obj.Spec.TargetAPIVersion = "cert-manager.io/v1"
+			obj.Spec.TargetKind = "Certificate"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.match.podMatch"))
+
+			obj.Spec.Match = &autolabellerv1alpha1.MatchCriteria{
+				FieldMatch: []autolabellerv1alpha1.FieldRequirement{{Path: "spec.issuerRef[", Value: "letsencrypt"}},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.match.fieldMatch[0].path"))
+		})
+
+		It("Should deny unknown fields in nested criteria", func() {
+			// The misspelled podMach block decodes to an empty branch
+			obj.Spec.Match.AnyOf = []autolabellerv1alpha1.MatchCriteria{{}}
+			raw, err := json.Marshal(obj)
+			Expect(err).NotTo(HaveOccurred())
+			raw = bytes.Replace(raw, []byte(`"anyOf":[{}]`), []byte(`"anyOf":[{"podMach":{"images":["nginx"]}}]`), 1)
+			ctx = admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: raw},
+			}})
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`unknown field "anyOf[0].podMach"`))
+			Expect(err.Error()).To(ContainSubstring("spec.match.anyOf[0]: Required value"))
+		})
+
+		It("Should validate enums in nested criteria", func() {
+			obj.Spec.Match.Not = &autolabellerv1alpha1.MatchCriteria{
+				PodMatch: &autolabellerv1alpha1.PodMatchCriteria{MissingResourcePolicy: "Skip"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.match.not.podMatch.missingResourcePolicy"))
+		})
+
+		It("Should warn when a namespace is set for Node targets", func() {
+			obj.Spec.TargetKind = "Node"
+			obj.Spec.Match = &autolabellerv1alpha1.MatchCriteria{
+				CommonMatch: &autolabellerv1alpha1.CommonMatchCriteria{Namespace: "default"},
+			}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+	})
+
+	Context("When updating ClassificationRule under Validating Webhook", func() {
+		It("Should deny an update that introduces an invalid spec", func() {
+			obj.Spec.RefreshInterval = "-1m"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should allow metadata-only updates to an existing invalid rule", func() {
+			oldObj.Spec.RefreshInterval = "soon"
+			obj = oldObj.DeepCopy()
+			obj.Finalizers = nil
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})